@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/williamoconnorme/terraform-provider-servicefabric/internal/servicefabric"
+)
+
+// maxUpgradeInProgressRetries bounds how often an operation rejected with
+// FABRIC_E_APPLICATION_UPGRADE_IN_PROGRESS is retried.
+const maxUpgradeInProgressRetries = 5
+
+// operationCoordinator serializes conflicting cluster operations issued by
+// resources Terraform runs in parallel. It is shared by every resource of a
+// configured provider.
+//
+// Application upgrades, creates and deletes hold their application
+// exclusively; service operations share it, so they run alongside each other
+// but never during an upgrade started by this provider. Application type
+// provisions and unprovisions hold their type name exclusively.
+//
+// A nil coordinator performs no locking.
+type operationCoordinator struct {
+	mu    sync.Mutex
+	locks map[string]*operationLock
+}
+
+type operationLock struct {
+	refs    int
+	readers int
+	writer  bool
+	// released is closed and replaced each time the lock is released, waking
+	// every waiter so it can try again.
+	released chan struct{}
+}
+
+func newOperationCoordinator() *operationCoordinator {
+	return &operationCoordinator{locks: map[string]*operationLock{}}
+}
+
+// lockApplication holds the application exclusively until the returned func
+// is called.
+func (c *operationCoordinator) lockApplication(ctx context.Context, name string) (func(), error) {
+	return c.acquire(ctx, "application/"+name, true)
+}
+
+// shareApplication holds the application alongside other shared holders
+// until the returned func is called.
+func (c *operationCoordinator) shareApplication(ctx context.Context, name string) (func(), error) {
+	return c.acquire(ctx, "application/"+name, false)
+}
+
+// lockApplicationType holds the application type exclusively until the
+// returned func is called.
+func (c *operationCoordinator) lockApplicationType(ctx context.Context, name string) (func(), error) {
+	return c.acquire(ctx, "application_type/"+name, true)
+}
+
+func (c *operationCoordinator) acquire(ctx context.Context, key string, exclusive bool) (func(), error) {
+	if c == nil {
+		return func() {}, nil
+	}
+
+	c.mu.Lock()
+	lock, ok := c.locks[key]
+	if !ok {
+		lock = &operationLock{released: make(chan struct{})}
+		c.locks[key] = lock
+	}
+	lock.refs++
+	for {
+		free := !lock.writer && (!exclusive || lock.readers == 0)
+		if free {
+			if exclusive {
+				lock.writer = true
+			} else {
+				lock.readers++
+			}
+			c.mu.Unlock()
+			return func() { c.release(key, lock, exclusive) }, nil
+		}
+		released := lock.released
+		c.mu.Unlock()
+		tflog.Debug(ctx, "Waiting for conflicting Service Fabric operation", map[string]any{"key": key})
+
+		select {
+		case <-ctx.Done():
+			c.mu.Lock()
+			c.unref(key, lock)
+			c.mu.Unlock()
+			return nil, ctx.Err()
+		case <-released:
+		}
+		c.mu.Lock()
+	}
+}
+
+func (c *operationCoordinator) release(key string, lock *operationLock, exclusive bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if exclusive {
+		lock.writer = false
+	} else {
+		lock.readers--
+	}
+	close(lock.released)
+	lock.released = make(chan struct{})
+	c.unref(key, lock)
+}
+
+func (c *operationCoordinator) unref(key string, lock *operationLock) {
+	lock.refs--
+	if lock.refs == 0 {
+		delete(c.locks, key)
+	}
+}
+
+// runServiceOperation runs op, a change to a service of the application, once
+// no upgrade of the application is in flight, retrying it if the cluster
+// reports one anyway.
+func (c *operationCoordinator) runServiceOperation(ctx context.Context, client *servicefabric.Client, applicationName string, op func() error) error {
+	unlock, err := c.shareApplication(ctx, applicationName)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return retryDuringApplicationUpgrade(ctx, client, applicationName, op)
+}
+
+// retryDuringApplicationUpgrade runs op, and whenever the cluster rejects it
+// because the application is upgrading, waits for the upgrade to finish and
+// runs it again. This covers upgrades started outside this provider.
+func retryDuringApplicationUpgrade(ctx context.Context, client *servicefabric.Client, applicationName string, op func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || !servicefabric.IsApplicationUpgradeInProgressError(err) || attempt > maxUpgradeInProgressRetries {
+			return err
+		}
+		tflog.Info(ctx, "Application upgrade in progress, waiting before retrying", map[string]any{
+			"application_name": applicationName,
+			"attempt":          attempt,
+		})
+		if waitErr := client.WaitForApplicationUpgradeIdle(ctx, applicationName); waitErr != nil {
+			return waitErr
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/williamoconnorme/terraform-provider-servicefabric/internal/servicefabric"
+)
+
+// acquired reports whether the holder signals done before a short timeout.
+func acquired(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	case <-time.After(50 * time.Millisecond):
+		return false
+	}
+}
+
+func acquireAsync(t *testing.T, acquire func(context.Context, string) (func(), error)) (<-chan struct{}, func() func()) {
+	t.Helper()
+	done := make(chan struct{})
+	var unlock func()
+	go func() {
+		var err error
+		unlock, err = acquire(context.Background(), "fabric:/App")
+		if err != nil {
+			t.Errorf("acquire returned error: %s", err)
+		}
+		close(done)
+	}()
+	return done, func() func() { <-done; return unlock }
+}
+
+func TestOperationCoordinatorSharedHolders(t *testing.T) {
+	c := newOperationCoordinator()
+	ctx := context.Background()
+
+	first, err := c.shareApplication(ctx, "fabric:/App")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.shareApplication(ctx, "fabric:/App")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done, unlock := acquireAsync(t, c.lockApplication)
+	if acquired(done) {
+		t.Fatal("exclusive holder acquired the application while shared holders held it")
+	}
+	first()
+	if acquired(done) {
+		t.Fatal("exclusive holder acquired the application while a shared holder held it")
+	}
+	second()
+	if !acquired(done) {
+		t.Fatal("exclusive holder did not acquire the application once shared holders released it")
+	}
+	unlock()()
+}
+
+func TestOperationCoordinatorExclusiveHolder(t *testing.T) {
+	c := newOperationCoordinator()
+	ctx := context.Background()
+
+	unlock, err := c.lockApplication(ctx, "fabric:/App")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := c.lockApplication(ctx, "fabric:/Other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other()
+	typeUnlock, err := c.lockApplicationType(ctx, "fabric:/App")
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeUnlock()
+
+	shared, sharedUnlock := acquireAsync(t, c.shareApplication)
+	exclusive, exclusiveUnlock := acquireAsync(t, c.lockApplication)
+	if acquired(shared) || acquired(exclusive) {
+		t.Fatal("holder acquired the application while it was held exclusively")
+	}
+	unlock()
+
+	// Exactly one waiter wins; the other follows once it releases.
+	select {
+	case <-shared:
+		if acquired(exclusive) {
+			t.Fatal("exclusive holder acquired the application alongside a shared holder")
+		}
+		sharedUnlock()()
+		exclusiveUnlock()()
+	case <-exclusive:
+		if acquired(shared) {
+			t.Fatal("shared holder acquired the application alongside an exclusive holder")
+		}
+		exclusiveUnlock()()
+		sharedUnlock()()
+	case <-time.After(time.Second):
+		t.Fatal("no waiter acquired the application after it was released")
+	}
+
+	if len(c.locks) != 0 {
+		t.Errorf("locks = %v, want none after every holder released", c.locks)
+	}
+}
+
+func TestOperationCoordinatorCancelWhileWaiting(t *testing.T) {
+	c := newOperationCoordinator()
+
+	unlock, err := c.lockApplication(context.Background(), "fabric:/App")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := c.shareApplication(ctx, "fabric:/App")
+		errs <- err
+	}()
+	cancel()
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("shareApplication() error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("shareApplication() kept waiting after its context was cancelled")
+	}
+
+	c.mu.Lock()
+	refs := c.locks["application/fabric:/App"].refs
+	c.mu.Unlock()
+	if refs != 1 {
+		t.Errorf("refs = %d after the waiter gave up, want 1", refs)
+	}
+
+	unlock()
+	if len(c.locks) != 0 {
+		t.Errorf("locks = %v, want none after every holder released", c.locks)
+	}
+}
+
+func TestOperationCoordinatorNil(t *testing.T) {
+	var c *operationCoordinator
+	unlock, err := c.lockApplication(context.Background(), "fabric:/App")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+}
+
+func TestRetryDuringApplicationUpgrade(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/$/GetUpgradeProgress") {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"UpgradeState":"RollingForwardCompleted"}`))
+	}))
+	t.Cleanup(server.Close)
+	client, err := servicefabric.NewClient(servicefabric.ClientConfig{Endpoint: server.URL, ListCacheTTL: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	upgrading := &servicefabric.APIError{StatusCode: http.StatusConflict, Code: "FABRIC_E_APPLICATION_UPGRADE_IN_PROGRESS"}
+	tests := []struct {
+		name      string
+		failures  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "succeeds first time", failures: 0, wantCalls: 1},
+		{name: "succeeds after upgrade", failures: 2, wantCalls: 3},
+		{name: "succeeds on last retry", failures: maxUpgradeInProgressRetries, wantCalls: maxUpgradeInProgressRetries + 1},
+		{name: "gives up", failures: maxUpgradeInProgressRetries + 10, wantCalls: maxUpgradeInProgressRetries + 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := retryDuringApplicationUpgrade(context.Background(), client, "fabric:/App", func() error {
+				calls++
+				if calls <= tt.failures {
+					return upgrading
+				}
+				return nil
+			})
+			if calls != tt.wantCalls {
+				t.Errorf("op ran %d times, want %d", calls, tt.wantCalls)
+			}
+			if tt.wantErr != servicefabric.IsApplicationUpgradeInProgressError(err) || (!tt.wantErr && err != nil) {
+				t.Errorf("retryDuringApplicationUpgrade() error = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		calls := 0
+		want := errors.New("boom")
+		err := retryDuringApplicationUpgrade(context.Background(), client, "fabric:/App", func() error {
+			calls++
+			return want
+		})
+		if calls != 1 || !errors.Is(err, want) {
+			t.Errorf("op ran %d times with error %v, want once with %v", calls, err, want)
+		}
+	})
+}
@@ -3,10 +3,15 @@ package provider
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/williamoconnorme/terraform-provider-servicefabric/internal/servicefabric"
 )
@@ -21,16 +26,20 @@ type applicationTypeDataSourceModel struct {
 	ID                types.String `tfsdk:"id"`
 	Name              types.String `tfsdk:"name"`
 	Version           types.String `tfsdk:"version"`
+	VersionConstraint types.String `tfsdk:"version_constraint"`
 	Status            types.String `tfsdk:"status"`
 	DefaultParameters types.Map    `tfsdk:"default_parameters"`
 	ApplicationTypes  types.List   `tfsdk:"application_types"`
+	Latest            types.Object `tfsdk:"latest"`
 }
 
 var applicationTypeItemAttrTypes = map[string]attr.Type{
 	"name":               types.StringType,
 	"version":            types.StringType,
 	"status":             types.StringType,
+	"definition_kind":    types.StringType,
 	"default_parameters": types.MapType{ElemType: types.StringType},
+	"applications":       types.ListType{ElemType: types.StringType},
 }
 
 var applicationTypeItemObjectType = types.ObjectType{
@@ -45,6 +54,37 @@ func (d *applicationTypeDataSource) Metadata(_ context.Context, req datasource.M
 	resp.TypeName = req.ProviderTypeName + "_application_type"
 }
 
+func applicationTypeItemAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Computed:    true,
+			Description: "Application type name.",
+		},
+		"version": schema.StringAttribute{
+			Computed:    true,
+			Description: "Application type version.",
+		},
+		"status": schema.StringAttribute{
+			Computed:    true,
+			Description: "Provisioning status of the application type.",
+		},
+		"definition_kind": schema.StringAttribute{
+			Computed:    true,
+			Description: "How the application type was defined: ServiceFabricApplicationPackage or Compose.",
+		},
+		"default_parameters": schema.MapAttribute{
+			ElementType: types.StringType,
+			Computed:    true,
+			Description: "Default parameters for the application type.",
+		},
+		"applications": schema.ListAttribute{
+			ElementType: types.StringType,
+			Computed:    true,
+			Description: "Names of the applications currently running this version.",
+		},
+	}
+}
+
 func (d *applicationTypeDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
@@ -60,9 +100,17 @@ func (d *applicationTypeDataSource) Schema(_ context.Context, _ datasource.Schem
 				Optional:    true,
 				Description: "Application type version. Requires name. When omitted, all versions are returned.",
 			},
+			"version_constraint": schema.StringAttribute{
+				Optional:    true,
+				Description: "Version constraint such as \"~> 2.3\" or \">= 1.0, < 2.0\". Versions are compared semantically.",
+			},
 			"status": schema.StringAttribute{
+				Optional:    true,
 				Computed:    true,
-				Description: "Provisioning status.",
+				Description: "Only return versions with the given provisioning status. Populated with the status when a single result is returned.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("Available", "Provisioning", "Unprovisioning", "Failed", "Invalid"),
+				},
 			},
 			"default_parameters": schema.MapAttribute{
 				ElementType: types.StringType,
@@ -71,29 +119,16 @@ func (d *applicationTypeDataSource) Schema(_ context.Context, _ datasource.Schem
 			},
 			"application_types": schema.ListNestedAttribute{
 				Computed:    true,
-				Description: "List of application types matching the given filters.",
+				Description: "List of application types matching the given filters, ordered by name and then by version.",
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"name": schema.StringAttribute{
-							Computed:    true,
-							Description: "Application type name.",
-						},
-						"version": schema.StringAttribute{
-							Computed:    true,
-							Description: "Application type version.",
-						},
-						"status": schema.StringAttribute{
-							Computed:    true,
-							Description: "Provisioning status of the application type.",
-						},
-						"default_parameters": schema.MapAttribute{
-							ElementType: types.StringType,
-							Computed:    true,
-							Description: "Default parameters for the application type.",
-						},
-					},
+					Attributes: applicationTypeItemAttributes(),
 				},
 			},
+			"latest": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "Highest matching version. Only populated when all matching versions belong to the same application type.",
+				Attributes:  applicationTypeItemAttributes(),
+			},
 		},
 	}
 }
@@ -124,6 +159,7 @@ func (d *applicationTypeDataSource) Read(ctx context.Context, req datasource.Rea
 	if !state.Version.IsNull() {
 		version = state.Version.ValueString()
 	}
+	statusFilter, _ := stringValue(state.Status)
 
 	// version without name is invalid.
 	if version != "" && name == "" {
@@ -134,6 +170,16 @@ func (d *applicationTypeDataSource) Read(ctx context.Context, req datasource.Rea
 		return
 	}
 
+	var constraints []versionConstraint
+	if raw, ok := stringValue(state.VersionConstraint); ok {
+		parsed, err := parseVersionConstraints(raw)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("version_constraint"), "Invalid version constraint", err.Error())
+			return
+		}
+		constraints = parsed
+	}
+
 	var (
 		infos []servicefabric.ApplicationTypeInfo
 		err   error
@@ -156,14 +202,50 @@ func (d *applicationTypeDataSource) Read(ctx context.Context, req datasource.Rea
 		return
 	}
 
+	filtered := infos[:0]
+	for _, info := range infos {
+		if statusFilter != "" && !strings.EqualFold(info.Status, statusFilter) {
+			continue
+		}
+		if len(constraints) > 0 && !matchVersionConstraints(info.TypeVersion(), constraints) {
+			continue
+		}
+		filtered = append(filtered, info)
+	}
+	infos = filtered
+
 	if len(infos) == 0 {
 		resp.Diagnostics.AddError(
 			"Application type not found",
-			fmt.Sprintf("No application types matched name %q and version %q.", name, version),
+			fmt.Sprintf("No application types matched name %q, version %q, version constraint %q and status %q.", name, version, state.VersionConstraint.ValueString(), statusFilter),
 		)
 		return
 	}
 
+	sort.SliceStable(infos, func(i, j int) bool {
+		if c := strings.Compare(strings.ToLower(infos[i].TypeName()), strings.ToLower(infos[j].TypeName())); c != 0 {
+			return c < 0
+		}
+		return compareApplicationTypeVersions(infos[i].TypeVersion(), infos[j].TypeVersion()) < 0
+	})
+
+	// applications is supplementary; when the listing fails it is left null
+	// rather than failing the read.
+	var appsByVersion map[string][]attr.Value
+	apps, err := d.client.ListApplications(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddWarning(
+			"Failed to list applications",
+			fmt.Sprintf("The applications attribute is left unset: %s", err),
+		)
+	} else {
+		appsByVersion = make(map[string][]attr.Value)
+		for _, app := range apps {
+			key := strings.ToLower(applicationTypeID(app.TypeName, app.TypeVersion))
+			appsByVersion[key] = append(appsByVersion[key], types.StringValue(app.Name))
+		}
+	}
+
 	// Prepare list output.
 	itemValues := make([]attr.Value, 0, len(infos))
 	for _, info := range infos {
@@ -173,11 +255,22 @@ func (d *applicationTypeDataSource) Read(ctx context.Context, req datasource.Rea
 			paramsVal = types.MapValueMust(types.StringType, convertStringMapToAttrValues(params))
 		}
 
+		applications := types.ListNull(types.StringType)
+		if appsByVersion != nil {
+			usedBy := appsByVersion[strings.ToLower(applicationTypeID(info.TypeName(), info.TypeVersion()))]
+			if usedBy == nil {
+				usedBy = []attr.Value{}
+			}
+			applications = types.ListValueMust(types.StringType, usedBy)
+		}
+
 		objVal, diag := types.ObjectValue(applicationTypeItemAttrTypes, map[string]attr.Value{
 			"name":               types.StringValue(info.TypeName()),
 			"version":            types.StringValue(info.TypeVersion()),
 			"status":             types.StringValue(info.Status),
+			"definition_kind":    types.StringValue(info.ApplicationTypeDefinitionKind),
 			"default_parameters": paramsVal,
+			"applications":       applications,
 		})
 		resp.Diagnostics.Append(diag...)
 		if resp.Diagnostics.HasError() {
@@ -201,6 +294,13 @@ func (d *applicationTypeDataSource) Read(ctx context.Context, req datasource.Rea
 	state.ID = types.StringNull()
 	state.Status = types.StringNull()
 	state.DefaultParameters = types.MapNull(types.StringType)
+	state.Latest = types.ObjectNull(applicationTypeItemAttrTypes)
+
+	// Items are sorted by name, so a single type name means the last item is
+	// the highest version.
+	if strings.EqualFold(infos[0].TypeName(), infos[len(infos)-1].TypeName()) {
+		state.Latest = itemValues[len(itemValues)-1].(types.Object)
+	}
 
 	// Preserve provided filters.
 	if name != "" {
@@ -213,13 +313,13 @@ func (d *applicationTypeDataSource) Read(ctx context.Context, req datasource.Rea
 	} else {
 		state.Version = types.StringNull()
 	}
+	if statusFilter != "" {
+		state.Status = types.StringValue(statusFilter)
+	}
 
 	// When a single entry is returned, populate convenience attributes.
-	var target *servicefabric.ApplicationTypeInfo
 	if len(infos) == 1 {
-		target = &infos[0]
-	}
-	if target != nil {
+		target := infos[0]
 		state.ID = types.StringValue(fmt.Sprintf("%s/%s", target.TypeName(), target.TypeVersion()))
 		state.Name = types.StringValue(target.TypeName())
 		state.Version = types.StringValue(target.TypeVersion())
@@ -228,17 +328,7 @@ func (d *applicationTypeDataSource) Read(ctx context.Context, req datasource.Rea
 		params := servicefabric.ParameterListToMap(target.DefaultParameterList)
 		if len(params) > 0 {
 			state.DefaultParameters = types.MapValueMust(types.StringType, convertStringMapToAttrValues(params))
-		} else {
-			state.DefaultParameters = types.MapNull(types.StringType)
-		}
-	}
-	if target == nil {
-		// Keep defaults when not a single target.
-		if state.DefaultParameters.IsNull() {
-			state.DefaultParameters = types.MapNull(types.StringType)
 		}
-		state.Status = types.StringNull()
-		state.ID = types.StringNull()
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
@@ -258,28 +258,33 @@ func (d *serviceTypeDataSource) Read(ctx context.Context, req datasource.ReadReq
 }
 
 type serviceTypeDetails struct {
-	Name              string
-	Kind              string
-	HasPersistedState *bool
-	DescriptionJSON   string
+	Name                 string
+	Kind                 string
+	HasPersistedState    *bool
+	PlacementConstraints string
+	IsServiceGroup       bool
+	DescriptionJSON      string
 }
 
 func extractServiceTypeDetails(info servicefabric.ServiceTypeInfo) serviceTypeDetails {
 	result := serviceTypeDetails{
+		IsServiceGroup:  info.IsServiceGroup,
 		DescriptionJSON: strings.TrimSpace(string(info.ServiceTypeDescription)),
 	}
 	if len(info.ServiceTypeDescription) == 0 {
 		return result
 	}
 	var payload struct {
-		ServiceTypeName   string `json:"ServiceTypeName"`
-		Kind              string `json:"Kind"`
-		HasPersistedState *bool  `json:"HasPersistedState"`
+		ServiceTypeName      string `json:"ServiceTypeName"`
+		Kind                 string `json:"Kind"`
+		HasPersistedState    *bool  `json:"HasPersistedState"`
+		PlacementConstraints string `json:"PlacementConstraints"`
 	}
 	if err := json.Unmarshal(info.ServiceTypeDescription, &payload); err == nil {
 		result.Name = payload.ServiceTypeName
 		result.Kind = payload.Kind
 		result.HasPersistedState = payload.HasPersistedState
+		result.PlacementConstraints = payload.PlacementConstraints
 	}
 	return result
 }
@@ -1,11 +1,18 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/williamoconnorme/terraform-provider-servicefabric/internal/servicefabric"
 )
 
@@ -47,6 +54,39 @@ func splitApplicationCompositeID(id string) (string, string, bool) {
 	return parts[0], parts[1], true
 }
 
+func applicationTypeID(name, version string) string {
+	return name + "/" + version
+}
+
+func splitApplicationTypeID(id string) (string, string) {
+	parts := strings.SplitN(strings.TrimSpace(id), "/", 2)
+	if len(parts) != 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// clusterFeatureUse ties a configured attribute to the cluster capability it needs.
+type clusterFeatureUse struct {
+	path    path.Path
+	feature servicefabric.Feature
+}
+
+// checkClusterFeatures reports an error for every configured attribute the
+// targeted cluster is too old to honour.
+func checkClusterFeatures(client *servicefabric.Client, uses []clusterFeatureUse) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if client == nil {
+		return diags
+	}
+	for _, use := range uses {
+		if err := client.CheckFeature(use.feature); err != nil {
+			diags.AddAttributeError(use.path, "Attribute requires a newer Service Fabric cluster", err.Error())
+		}
+	}
+	return diags
+}
+
 func serviceKindFromInfo(info servicefabric.ServiceInfo) string {
 	if info.ServiceKind != "" {
 		return info.ServiceKind
@@ -90,3 +130,44 @@ func boolValue(v types.Bool) (bool, bool) {
 	}
 	return v.ValueBool(), true
 }
+
+// upgradeRawState rewrites the JSON attributes of a prior state whose schema is
+// otherwise compatible with the current one.
+func upgradeRawState(req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse, rewrite func(attrs map[string]any)) {
+	if req.RawState == nil || len(req.RawState.JSON) == 0 {
+		resp.Diagnostics.AddError("Unable to upgrade state", "The prior state is not available in JSON format.")
+		return
+	}
+	var attrs map[string]any
+	if err := json.Unmarshal(req.RawState.JSON, &attrs); err != nil {
+		resp.Diagnostics.AddError("Unable to upgrade state", fmt.Sprintf("Failed to decode prior state: %s", err))
+		return
+	}
+	rewrite(attrs)
+	upgraded, err := json.Marshal(attrs)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to upgrade state", fmt.Sprintf("Failed to encode upgraded state: %s", err))
+		return
+	}
+	resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+}
+
+// checkDeletionProtection reports an error when state has
+// deletion_protection enabled. It runs both when planning a destroy and in
+// Delete, which also covers replacements.
+func checkDeletionProtection(ctx context.Context, state tfsdk.State, diags *diag.Diagnostics, kind string) {
+	if state.Raw.IsNull() {
+		return
+	}
+	var protected types.Bool
+	var name types.String
+	diags.Append(state.GetAttribute(ctx, path.Root("deletion_protection"), &protected)...)
+	diags.Append(state.GetAttribute(ctx, path.Root("name"), &name)...)
+	if diags.HasError() || !protected.ValueBool() {
+		return
+	}
+	diags.AddError(
+		"Deletion protection enabled",
+		fmt.Sprintf("The %s %s has deletion_protection enabled. Set deletion_protection = false and apply that change before destroying or replacing it.", kind, name.ValueString()),
+	)
+}
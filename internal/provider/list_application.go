@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ list.ListResourceWithConfigure = &applicationListResource{}
+
+// applicationListResource lists applications deployed in the cluster so they
+// can be discovered with terraform query.
+type applicationListResource struct {
+	applicationResource
+}
+
+type applicationListConfigModel struct {
+	TypeName   types.String `tfsdk:"type_name"`
+	NamePrefix types.String `tfsdk:"name_prefix"`
+}
+
+func NewApplicationListResource() list.ListResource {
+	return &applicationListResource{}
+}
+
+func (r *applicationListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = listschema.Schema{
+		Attributes: map[string]listschema.Attribute{
+			"type_name": listschema.StringAttribute{
+				Optional:    true,
+				Description: "Only return applications created from the given application type name.",
+			},
+			"name_prefix": listschema.StringAttribute{
+				Optional:    true,
+				Description: "Only return applications whose name starts with the given prefix, e.g. fabric:/Contoso.",
+			},
+		},
+	}
+}
+
+func (r *applicationListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config applicationListConfigModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	typeName, _ := stringValue(config.TypeName)
+	prefix, _ := stringValue(config.NamePrefix)
+	prefix = strings.TrimSpace(prefix)
+	if prefix != "" && !strings.HasPrefix(prefix, "fabric:") {
+		prefix = "fabric:/" + strings.TrimPrefix(prefix, "/")
+	}
+
+	infos, err := r.client.ListApplications(ctx, typeName)
+	if err != nil {
+		diags.AddError("Failed to list applications", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var emitted int64
+		for i := range infos {
+			info := infos[i]
+			if prefix != "" && !strings.HasPrefix(info.Name, prefix) {
+				continue
+			}
+			if req.Limit > 0 && emitted >= req.Limit {
+				return
+			}
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = info.Name
+
+			state := applicationResourceModel{
+				DeletionProtection: types.BoolValue(false),
+				ForceRemove:        types.BoolValue(false),
+				WaitForUpgrade:     types.BoolValue(true),
+			}
+			applyUpgradeProgress(&state, nil)
+			if err := applyApplicationInfo(ctx, &state, &info); err != nil {
+				result.Diagnostics.AddError("Failed to read application", err.Error())
+			} else {
+				result.Diagnostics.Append(setApplicationIdentity(ctx, result.Identity, state)...)
+				if req.IncludeResource {
+					result.Diagnostics.Append(result.Resource.Set(ctx, state)...)
+				}
+			}
+
+			emitted++
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
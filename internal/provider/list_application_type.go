@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ list.ListResourceWithConfigure = &applicationTypeListResource{}
+
+// applicationTypeListResource lists application type versions registered in the
+// cluster so they can be discovered with terraform query.
+type applicationTypeListResource struct {
+	applicationTypeResource
+}
+
+type applicationTypeListConfigModel struct {
+	Name types.String `tfsdk:"name"`
+}
+
+func NewApplicationTypeListResource() list.ListResource {
+	return &applicationTypeListResource{}
+}
+
+func (r *applicationTypeListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = listschema.Schema{
+		Attributes: map[string]listschema.Attribute{
+			"name": listschema.StringAttribute{
+				Optional:    true,
+				Description: "Only return versions of the given application type name.",
+			},
+		},
+	}
+}
+
+func (r *applicationTypeListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config applicationTypeListConfigModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	typeName, _ := stringValue(config.Name)
+	infos, err := r.client.ListApplicationTypeVersions(ctx, typeName)
+	if err != nil {
+		diags.AddError("Failed to list application types", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var emitted int64
+		for i := range infos {
+			if req.Limit > 0 && emitted >= req.Limit {
+				return
+			}
+			info := infos[i]
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = fmt.Sprintf("%s %s", info.TypeName(), info.TypeVersion())
+
+			state := applicationTypeResourceModel{
+				Name:               types.StringValue(info.TypeName()),
+				Version:            types.StringValue(info.TypeVersion()),
+				PackageURI:         types.StringNull(),
+				RetainVersions:     types.BoolValue(false),
+				DeletionProtection: types.BoolValue(false),
+				VersionRetention:   types.ObjectNull(versionRetentionAttrTypes),
+			}
+			r.applyInfoToState(&state, &info)
+
+			result.Diagnostics.Append(setApplicationTypeIdentity(ctx, result.Identity, state)...)
+			if req.IncludeResource {
+				result.Diagnostics.Append(result.Resource.Set(ctx, state)...)
+			}
+
+			emitted++
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/williamoconnorme/terraform-provider-servicefabric/internal/servicefabric"
+)
+
+var _ list.ListResourceWithConfigure = &serviceListResource{}
+
+// serviceListResource lists services in one or all applications so they can be
+// discovered with terraform query.
+type serviceListResource struct {
+	serviceResource
+}
+
+type serviceListConfigModel struct {
+	ApplicationName types.String `tfsdk:"application_name"`
+	ServiceTypeName types.String `tfsdk:"service_type_name"`
+}
+
+func NewServiceListResource() list.ListResource {
+	return &serviceListResource{}
+}
+
+func (r *serviceListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = listschema.Schema{
+		Attributes: map[string]listschema.Attribute{
+			"application_name": listschema.StringAttribute{
+				Optional:    true,
+				Description: "Full Service Fabric application name (fabric:/...) whose services are listed. When omitted, services of every application are returned.",
+			},
+			"service_type_name": listschema.StringAttribute{
+				Optional:    true,
+				Description: "Only return services of the given service type name.",
+			},
+		},
+	}
+}
+
+func (r *serviceListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config serviceListConfigModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	serviceTypeName, _ := stringValue(config.ServiceTypeName)
+
+	var applicationNames []string
+	if appName, ok := stringValue(config.ApplicationName); ok && appName != "" {
+		applicationNames = []string{appName}
+	} else {
+		apps, err := r.client.ListApplications(ctx, "")
+		if err != nil {
+			diags.AddError("Failed to list applications", err.Error())
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+		for _, app := range apps {
+			applicationNames = append(applicationNames, app.Name)
+		}
+	}
+
+	var infos []servicefabric.ServiceInfo
+	for _, appName := range applicationNames {
+		services, err := r.client.ListServices(ctx, appName, serviceTypeName)
+		if err != nil {
+			if servicefabric.IsNotFoundError(err) {
+				continue
+			}
+			diags.AddError("Failed to list services", err.Error())
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+		infos = append(infos, services...)
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var emitted int64
+		for i := range infos {
+			if req.Limit > 0 && emitted >= req.Limit {
+				return
+			}
+			info := infos[i]
+			// Service groups are managed by servicefabric_service_group.
+			if info.IsServiceGroup {
+				continue
+			}
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = info.Name
+
+			state := serviceResourceModel{
+				ForceRemove:        types.BoolValue(false),
+				DeletionProtection: types.BoolValue(false),
+				Partition:          types.ObjectNull(partitionAttrTypes),
+				Stateless:          types.ObjectNull(statelessServiceAttrTypes),
+				Stateful:           types.ObjectNull(statefulServiceAttrTypes),
+			}
+			r.applyInfoToState(&state, &info)
+
+			result.Diagnostics.Append(setServiceIdentity(ctx, result.Identity, state)...)
+			if req.IncludeResource {
+				result.Diagnostics.Append(result.Resource.Set(ctx, state)...)
+			}
+
+			emitted++
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
@@ -8,8 +8,10 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -22,6 +24,7 @@ import (
 
 // Ensure provider satisfies the interface.
 var _ provider.Provider = &serviceFabricProvider{}
+var _ provider.ProviderWithListResources = &serviceFabricProvider{}
 
 // New instantiates the Service Fabric provider.
 func New() provider.Provider {
@@ -30,33 +33,70 @@ func New() provider.Provider {
 
 // serviceFabricProviderModel defines the provider configuration model.
 type serviceFabricProviderModel struct {
-	Endpoint                     types.String `tfsdk:"endpoint"`
-	SkipTLSVerify                types.Bool   `tfsdk:"skip_tls_verify"`
-	ClusterApplicationID         types.String `tfsdk:"cluster_application_id"`
-	TenantID                     types.String `tfsdk:"tenant_id"`
-	ClientID                     types.String `tfsdk:"client_id"`
-	ClientSecret                 types.String `tfsdk:"client_secret"`
-	DefaultCredentialType        types.String `tfsdk:"default_credential_type"`
-	ClientCertificatePath        types.String `tfsdk:"client_certificate_path"`
-	ClientCertificatePassword    types.String `tfsdk:"client_certificate_password"`
-	ApplicationRecreateOnUpgrade types.Bool   `tfsdk:"application_recreate_on_upgrade"`
-	AllowApplicationTypeUpdates  types.Bool   `tfsdk:"allow_application_type_version_updates"`
+	Endpoint                     types.String               `tfsdk:"endpoint"`
+	Endpoints                    types.List                 `tfsdk:"endpoints"`
+	SkipTLSVerify                types.Bool                 `tfsdk:"skip_tls_verify"`
+	ServerCertThumbprints        types.List                 `tfsdk:"server_certificate_thumbprints"`
+	ServerCertCommonNames        types.List                 `tfsdk:"server_certificate_common_names"`
+	ServerCertIssuerThumbprints  types.List                 `tfsdk:"server_certificate_issuer_thumbprints"`
+	CACertificatePath            types.String               `tfsdk:"ca_certificate_path"`
+	ClusterApplicationID         types.String               `tfsdk:"cluster_application_id"`
+	TenantID                     types.String               `tfsdk:"tenant_id"`
+	ClientID                     types.String               `tfsdk:"client_id"`
+	ClientSecret                 types.String               `tfsdk:"client_secret"`
+	DefaultCredentialType        types.String               `tfsdk:"default_credential_type"`
+	Environment                  types.String               `tfsdk:"environment"`
+	AuthorityHost                types.String               `tfsdk:"authority_host"`
+	OIDCToken                    types.String               `tfsdk:"oidc_token"`
+	OIDCTokenFilePath            types.String               `tfsdk:"oidc_token_file_path"`
+	ClientCertificatePath        types.String               `tfsdk:"client_certificate_path"`
+	ClientCertificate            types.String               `tfsdk:"client_certificate"`
+	ClientCertificateKeyPath     types.String               `tfsdk:"client_certificate_key_path"`
+	ClientCertificateCandidates  types.List                 `tfsdk:"client_certificate_candidates"`
+	ClientCertificateExpiryDays  types.Int64                `tfsdk:"client_certificate_expiry_warning_days"`
+	ClientCertificatePassword    types.String               `tfsdk:"client_certificate_password"`
+	ListCacheTTLSeconds          types.Int64                `tfsdk:"list_cache_ttl_seconds"`
+	ApplicationRecreateOnUpgrade types.Bool                 `tfsdk:"application_recreate_on_upgrade"`
+	AllowApplicationTypeUpdates  types.Bool                 `tfsdk:"allow_application_type_version_updates"`
+	HTTPTracing                  types.Bool                 `tfsdk:"http_tracing"`
+	HTTPTracingRedactedParams    types.List                 `tfsdk:"http_tracing_redacted_parameters"`
+	SfctlConfigPath              types.String               `tfsdk:"sfctl_config_path"`
+	DefaultApplicationParameters types.Map                  `tfsdk:"default_application_parameters"`
+	DefaultUpgradePolicy         *defaultUpgradePolicyModel `tfsdk:"default_upgrade_policy"`
 }
 
 type serviceFabricProvider struct{}
 
 type providerFeatures struct {
-	ApplicationRecreateOnUpgrade bool
-	AllowApplicationTypeUpdates  bool
+	// ApplicationConflictStrategy is the replacement strategy applied when an
+	// application being created already exists and the resource does not set
+	// replacement_strategy. It is empty, and the create fails, unless the
+	// deprecated application_recreate_on_upgrade flag is set.
+	ApplicationConflictStrategy string
+	AllowApplicationTypeUpdates bool
 }
 
 var allowApplicationTypeUpdatesFlag atomic.Bool
 
 type providerData struct {
-	Client   *servicefabric.Client
-	Features providerFeatures
+	Client              *servicefabric.Client
+	Features            providerFeatures
+	ApplicationDefaults applicationDefaults
+	Coordinator         *operationCoordinator
 }
 
+// clientCertificateCandidateModel is a fallback client certificate.
+type clientCertificateCandidateModel struct {
+	Path     types.String `tfsdk:"path"`
+	Content  types.String `tfsdk:"content"`
+	KeyPath  types.String `tfsdk:"key_path"`
+	Password types.String `tfsdk:"password"`
+}
+
+// defaultCertificateExpiryWarningDays is used when
+// client_certificate_expiry_warning_days is not set.
+const defaultCertificateExpiryWarningDays = 30
+
 // Metadata returns the provider type name.
 func (p *serviceFabricProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
 	resp.TypeName = "servicefabric"
@@ -67,13 +107,37 @@ func (p *serviceFabricProvider) Schema(_ context.Context, _ provider.SchemaReque
 	resp.Schema = providerschema.Schema{
 		Attributes: map[string]providerschema.Attribute{
 			"endpoint": providerschema.StringAttribute{
-				Required:    true,
-				Description: "Service Fabric cluster HTTPS management endpoint, e.g. https://cluster:19080.",
+				Optional:    true,
+				Description: "Service Fabric cluster HTTPS management endpoint, e.g. https://cluster:19080. Falls back to SF_ENDPOINT and then the sfctl connection profile.",
+			},
+			"endpoints": providerschema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "HTTP gateway endpoints of the cluster's nodes. Requests fail over to the next endpoint when a node is unreachable or returns 503, and stay on the endpoint that answered. When endpoint is also set it is tried first.",
 			},
 			"skip_tls_verify": providerschema.BoolAttribute{
 				Optional:    true,
 				Description: "Skip verification of the server's TLS certificate. Use only for development.",
 			},
+			"server_certificate_thumbprints": providerschema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "SHA-1 or SHA-256 thumbprints of server certificates to accept, equivalent to sfctl's server certificate thumbprints. Hostname verification is not performed for pinned certificates.",
+			},
+			"server_certificate_common_names": providerschema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Subject common names of server certificates to accept, equivalent to RemoteCommonNames in Service Fabric X509 credentials. The chain must be trusted by ca_certificate_path, or the system roots when it is unset, unless server_certificate_issuer_thumbprints is set.",
+			},
+			"server_certificate_issuer_thumbprints": providerschema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Thumbprints of the issuers allowed to sign certificates matched by server_certificate_common_names, equivalent to IssuerThumbprints in Service Fabric X509 credentials.",
+			},
+			"ca_certificate_path": providerschema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a PEM bundle of CA certificates trusted for the cluster's server certificate instead of the system roots.",
+			},
 			"cluster_application_id": providerschema.StringAttribute{
 				Optional:    true,
 				Description: "Service Fabric server application ID used when requesting Entra tokens.",
@@ -98,23 +162,114 @@ func (p *serviceFabricProvider) Schema(_ context.Context, _ provider.SchemaReque
 					stringvalidator.OneOf("default", "environment", "workload_identity", "managed_identity", "azure_cli", "azure_developer_cli", "azure_powershell"),
 				},
 			},
+			"environment": providerschema.StringAttribute{
+				Optional:    true,
+				Description: "Azure cloud used for Entra authentication: \"public\" (default), \"usgovernment\" or \"china\".",
+				Validators: []schemavalidator.String{
+					stringvalidator.OneOf("public", "usgovernment", "china"),
+				},
+			},
+			"authority_host": providerschema.StringAttribute{
+				Optional:    true,
+				Description: "Entra authority host overriding the one implied by environment, e.g. https://login.microsoftonline.us/.",
+			},
+			"oidc_token": providerschema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Federated identity token (e.g. from GitHub Actions, GitLab or Azure DevOps) exchanged for an Entra token as a client assertion. Requires tenant_id and client_id.",
+			},
+			"oidc_token_file_path": providerschema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a file holding a federated identity token. The file is re-read whenever a new Entra token is needed. Conflicts with oidc_token.",
+			},
 			"client_certificate_path": providerschema.StringAttribute{
 				Optional:    true,
-				Description: "Path to a client certificate in PFX/PKCS#12 format used for certificate authentication.",
+				Description: "Path to a client certificate used for certificate authentication, either PFX/PKCS#12 or PEM. A PEM file may contain the private key and intermediate certificates.",
+			},
+			"client_certificate": providerschema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Base64-encoded PFX/PKCS#12 or PEM client certificate content. Conflicts with client_certificate_path.",
+			},
+			"client_certificate_key_path": providerschema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a PEM private key for a PEM client certificate that does not include its key. Encrypted PKCS#8 keys are decrypted with client_certificate_password.",
 			},
 			"client_certificate_password": providerschema.StringAttribute{
 				Optional:    true,
 				Sensitive:   true,
-				Description: "Password for the client certificate when using certificate authentication.",
+				Description: "Password for a PFX client certificate or an encrypted PKCS#8 private key.",
 			},
-			"application_recreate_on_upgrade": providerschema.BoolAttribute{
+			"client_certificate_candidates": providerschema.ListNestedAttribute{
+				Optional:    true,
+				Description: "Fallback client certificates tried in order when the cluster rejects the active one, e.g. the next certificate during a rotation.",
+				NestedObject: providerschema.NestedAttributeObject{
+					Attributes: map[string]providerschema.Attribute{
+						"path": providerschema.StringAttribute{
+							Optional:    true,
+							Description: "Path to a PFX/PKCS#12 or PEM certificate.",
+						},
+						"content": providerschema.StringAttribute{
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Base64-encoded PFX/PKCS#12 or PEM certificate content. Conflicts with path.",
+						},
+						"key_path": providerschema.StringAttribute{
+							Optional:    true,
+							Description: "Path to a PEM private key for a PEM certificate that does not include its key.",
+						},
+						"password": providerschema.StringAttribute{
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Password for the PFX file or encrypted PKCS#8 private key.",
+						},
+					},
+				},
+			},
+			"client_certificate_expiry_warning_days": providerschema.Int64Attribute{
 				Optional:    true,
-				Description: "When true, replacements of existing applications trigger a Service Fabric upgrade with ForceRestart instead of deleting and recreating the application. Defaults to true.",
+				Description: "Warn when the selected client certificate expires within this many days. Defaults to 30; set to 0 to disable the warning.",
+				Validators: []schemavalidator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"list_cache_ttl_seconds": providerschema.Int64Attribute{
+				Optional:    true,
+				Description: "How long, in seconds, list responses are shared between resources. The cache lasts for the whole provider process, so reads can be this stale after a change made outside Terraform. Defaults to 30; set to 0 to disable caching.",
+				Validators: []schemavalidator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"application_recreate_on_upgrade": providerschema.BoolAttribute{
+				Optional:           true,
+				Description:        "When true, creating an application that already exists upgrades it with ForceRestart; when false or unset, the create fails. Only applies to applications that do not set replacement_strategy.",
+				DeprecationMessage: "Use replacement_strategy on servicefabric_application instead.",
 			},
 			"allow_application_type_version_updates": providerschema.BoolAttribute{
 				Optional:    true,
 				Description: "When true, version changes for servicefabric_application_type are applied in-place instead of forcing Terraform replacement. Use with caution: Terraform will treat the existing resource as updated even though the old version may remain registered in the cluster.",
 			},
+			"http_tracing": providerschema.BoolAttribute{
+				Optional:    true,
+				Description: "When true, every REST request and response is logged at DEBUG level to the provider's \"http\" log subsystem with credentials, SAS signatures and sensitive parameter values redacted.",
+			},
+			"http_tracing_redacted_parameters": providerschema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Application parameter name patterns (case-insensitive globs such as \"*Password*\") whose values are redacted from HTTP traces in addition to the built-in password, secret, token, connection string and account key patterns.",
+			},
+			"sfctl_config_path": providerschema.StringAttribute{
+				Optional:    true,
+				Description: "Path to an sfctl configuration file whose selected cluster is used when no endpoint is configured. Defaults to SF_SFCTL_CONFIG_PATH or ~/.sfctl/config.",
+			},
+			"default_application_parameters": providerschema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Application parameters supplied to every servicefabric_application. An application's own parameters take precedence.",
+			},
+		},
+		Blocks: map[string]providerschema.Block{
+			"default_upgrade_policy": defaultUpgradePolicyBlock(),
 		},
 	}
 }
@@ -127,11 +282,25 @@ func (p *serviceFabricProvider) Configure(ctx context.Context, req provider.Conf
 		return
 	}
 
-	if config.Endpoint.IsNull() || config.Endpoint.ValueString() == "" {
+	sources, diags := resolveProviderSettings(&config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, "Resolved Service Fabric provider settings", sources.logFields())
+
+	var endpoints []string
+	if !config.Endpoints.IsNull() && !config.Endpoints.IsUnknown() {
+		resp.Diagnostics.Append(config.Endpoints.ElementsAs(ctx, &endpoints, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if config.Endpoint.ValueString() == "" && len(endpoints) == 0 {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("endpoint"),
 			"Missing endpoint",
-			"The provider requires an endpoint value.",
+			"The provider requires an endpoint. Set endpoint or endpoints in the provider block, export SF_ENDPOINT, or select a cluster with `sfctl cluster select`.",
 		)
 		return
 	}
@@ -149,42 +318,150 @@ func (p *serviceFabricProvider) Configure(ctx context.Context, req provider.Conf
 		httpClient.Transport = transport
 	}
 
+	serverCertificate := servicefabric.ServerCertificateOptions{
+		CACertificatePath: config.CACertificatePath.ValueString(),
+	}
+	for _, item := range []struct {
+		value  types.List
+		target *[]string
+	}{
+		{config.ServerCertThumbprints, &serverCertificate.Thumbprints},
+		{config.ServerCertCommonNames, &serverCertificate.CommonNames},
+		{config.ServerCertIssuerThumbprints, &serverCertificate.IssuerThumbprints},
+	} {
+		if item.value.IsNull() || item.value.IsUnknown() {
+			continue
+		}
+		resp.Diagnostics.Append(item.value.ElementsAs(ctx, item.target, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if len(serverCertificate.IssuerThumbprints) > 0 && len(serverCertificate.CommonNames) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("server_certificate_issuer_thumbprints"),
+			"Missing server certificate common names",
+			"server_certificate_issuer_thumbprints only applies to certificates matched by server_certificate_common_names.",
+		)
+		return
+	}
+	pinsServerCertificate := len(serverCertificate.Thumbprints) > 0 || len(serverCertificate.CommonNames) > 0 || serverCertificate.CACertificatePath != ""
+	if pinsServerCertificate && config.SkipTLSVerify.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("skip_tls_verify"),
+			"Conflicting TLS settings",
+			"skip_tls_verify cannot be combined with server_certificate_thumbprints, server_certificate_common_names or ca_certificate_path.",
+		)
+		return
+	}
+	if err := servicefabric.ConfigureServerCertificateValidation(httpClient, serverCertificate); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to configure server certificate validation",
+			err.Error(),
+		)
+		return
+	}
+
 	var auth servicefabric.Authenticator
 	var err error
 
 	authMode := "entra"
 	defaultCredentialType := ""
+	// Credentials redacted from HTTP traces; candidates add their own below.
+	secrets := []string{
+		config.ClientCertificatePassword.ValueString(),
+		config.ClientSecret.ValueString(),
+		config.ClientCertificate.ValueString(),
+		config.OIDCToken.ValueString(),
+	}
 
-	useCertificate := !config.ClientCertificatePath.IsNull() && config.ClientCertificatePath.ValueString() != ""
+	useCertificate := config.ClientCertificatePath.ValueString() != "" || config.ClientCertificate.ValueString() != ""
+	if !useCertificate && !config.ClientCertificateCandidates.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_certificate_candidates"),
+			"Missing client certificate",
+			"client_certificate_candidates are fallbacks and require client_certificate_path or client_certificate.",
+		)
+		return
+	}
 
 	if useCertificate {
 		authMode = "certificate"
-		if config.ClientCertificatePath.IsNull() || config.ClientCertificatePath.ValueString() == "" {
+		if config.ClientCertificatePath.ValueString() != "" && config.ClientCertificate.ValueString() != "" {
 			resp.Diagnostics.AddAttributeError(
-				path.Root("client_certificate_path"),
-				"Missing client certificate",
-				"The provider requires client_certificate_path when using certificate authentication.",
+				path.Root("client_certificate"),
+				"Conflicting client certificate settings",
+				"Only one of client_certificate_path and client_certificate can be set.",
 			)
 			return
 		}
-		password := ""
-		if !config.ClientCertificatePassword.IsNull() {
-			password = config.ClientCertificatePassword.ValueString()
+		source := "inline client_certificate content" + sources.describe("client_certificate")
+		if config.ClientCertificatePath.ValueString() != "" {
+			source = fmt.Sprintf("%q%s", config.ClientCertificatePath.ValueString(), sources.describe("client_certificate_path"))
+		}
+		var candidateModels []clientCertificateCandidateModel
+		if !config.ClientCertificateCandidates.IsNull() && !config.ClientCertificateCandidates.IsUnknown() {
+			resp.Diagnostics.Append(config.ClientCertificateCandidates.ElementsAs(ctx, &candidateModels, false)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
 		}
-		auth, err = servicefabric.NewCertificateAuthenticator(config.ClientCertificatePath.ValueString(), password)
+		candidates := make([]servicefabric.CertificateOptions, 0, len(candidateModels))
+		for i, candidate := range candidateModels {
+			if (candidate.Path.ValueString() == "") == (candidate.Content.ValueString() == "") {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("client_certificate_candidates").AtListIndex(i),
+					"Invalid client certificate candidate",
+					"Exactly one of path and content must be set.",
+				)
+				return
+			}
+			candidates = append(candidates, servicefabric.CertificateOptions{
+				Path:     candidate.Path.ValueString(),
+				Content:  candidate.Content.ValueString(),
+				KeyPath:  candidate.KeyPath.ValueString(),
+				Password: candidate.Password.ValueString(),
+			})
+			secrets = append(secrets, candidate.Password.ValueString(), candidate.Content.ValueString())
+		}
+
+		auth, err = servicefabric.NewCertificateAuthenticator(servicefabric.CertificateOptions{
+			Path:     config.ClientCertificatePath.ValueString(),
+			Content:  config.ClientCertificate.ValueString(),
+			KeyPath:  config.ClientCertificateKeyPath.ValueString(),
+			Password: config.ClientCertificatePassword.ValueString(),
+		}, candidates...)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Failed to load client certificate",
-				fmt.Sprintf("Unable to read certificate at %q: %s", config.ClientCertificatePath.ValueString(), err),
+				fmt.Sprintf("Unable to read certificate from %s: %s", source, err),
 			)
 			return
 		}
+
+		warningDays := int64(defaultCertificateExpiryWarningDays)
+		if !config.ClientCertificateExpiryDays.IsNull() {
+			warningDays = config.ClientCertificateExpiryDays.ValueInt64()
+		}
+		if leaf := auth.(*servicefabric.CertificateAuthenticator).Certificate(); warningDays > 0 {
+			if remaining := time.Until(leaf.NotAfter); remaining < time.Duration(warningDays)*24*time.Hour {
+				expiry := "which has already passed"
+				if remaining > 0 {
+					expiry = fmt.Sprintf("in %d day(s)", int(remaining.Hours()/24))
+				}
+				resp.Diagnostics.AddWarning(
+					"Client certificate expires soon",
+					fmt.Sprintf("The client certificate %q (thumbprint %s) expires at %s, %s. Rotate it or add the replacement to client_certificate_candidates.",
+						leaf.Subject.CommonName, servicefabric.Thumbprint(leaf), leaf.NotAfter.UTC().Format(time.RFC3339), expiry),
+				)
+			}
+		}
 	} else {
 		if config.ClusterApplicationID.IsNull() || config.ClusterApplicationID.ValueString() == "" {
 			resp.Diagnostics.AddAttributeError(
 				path.Root("cluster_application_id"),
 				"Missing cluster application ID",
-				"The provider requires cluster_application_id when using Entra authentication.",
+				"The provider requires cluster_application_id (or SF_CLUSTER_APPLICATION_ID) when using Entra authentication, or a client certificate.",
 			)
 			return
 		}
@@ -204,6 +481,10 @@ func (p *serviceFabricProvider) Configure(ctx context.Context, req provider.Conf
 			defaultCredentialType = config.DefaultCredentialType.ValueString()
 			options.DefaultCredentialType = defaultCredentialType
 		}
+		options.Environment = config.Environment.ValueString()
+		options.AuthorityHost = config.AuthorityHost.ValueString()
+		options.OIDCToken = config.OIDCToken.ValueString()
+		options.OIDCTokenFilePath = config.OIDCTokenFilePath.ValueString()
 
 		auth, err = servicefabric.NewEntraAuthenticator(options)
 		if err != nil {
@@ -223,10 +504,39 @@ func (p *serviceFabricProvider) Configure(ctx context.Context, req provider.Conf
 		return
 	}
 
+	if !config.HTTPTracing.IsNull() && config.HTTPTracing.ValueBool() {
+		var redacted []string
+		if !config.HTTPTracingRedactedParams.IsNull() && !config.HTTPTracingRedactedParams.IsUnknown() {
+			resp.Diagnostics.Append(config.HTTPTracingRedactedParams.ElementsAs(ctx, &redacted, false)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+		var secretFiles []string
+		if tokenFile := config.OIDCTokenFilePath.ValueString(); tokenFile != "" {
+			secretFiles = append(secretFiles, tokenFile)
+		}
+		httpClient.Transport = servicefabric.NewTracingTransport(httpClient.Transport, servicefabric.TracingOptions{
+			RedactedParameters: redacted,
+			Secrets:            secrets,
+			SecretFiles:        secretFiles,
+		})
+	}
+
+	var listCacheTTL time.Duration
+	if seconds, ok := int64Value(config.ListCacheTTLSeconds); ok {
+		listCacheTTL = time.Duration(seconds) * time.Second
+		if seconds == 0 {
+			listCacheTTL = -1
+		}
+	}
+
 	client, err := servicefabric.NewClient(servicefabric.ClientConfig{
 		Endpoint:      config.Endpoint.ValueString(),
+		Endpoints:     endpoints,
 		HTTPClient:    httpClient,
 		Authenticator: auth,
+		ListCacheTTL:  listCacheTTL,
 	})
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -236,9 +546,19 @@ func (p *serviceFabricProvider) Configure(ctx context.Context, req provider.Conf
 		return
 	}
 
+	negotiateCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	clusterVersion, err := client.NegotiateAPIVersion(negotiateCtx)
+	cancel()
+	if err != nil {
+		tflog.Warn(ctx, "Unable to determine Service Fabric cluster version; using the default api-version", map[string]any{
+			"error": err.Error(),
+		})
+	}
+
 	logFields := map[string]any{
-		"endpoint": config.Endpoint.ValueString(),
-		"authMode": authMode,
+		"endpoint":       client.Endpoint(),
+		"authMode":       authMode,
+		"clusterVersion": clusterVersion,
 	}
 	if defaultCredentialType != "" {
 		logFields["defaultCredentialType"] = defaultCredentialType
@@ -246,24 +566,36 @@ func (p *serviceFabricProvider) Configure(ctx context.Context, req provider.Conf
 
 	tflog.Debug(ctx, "Service Fabric client configured", logFields)
 
-	features := providerFeatures{
-		ApplicationRecreateOnUpgrade: true,
-	}
-	if !config.ApplicationRecreateOnUpgrade.IsNull() && !config.ApplicationRecreateOnUpgrade.IsUnknown() {
-		features.ApplicationRecreateOnUpgrade = config.ApplicationRecreateOnUpgrade.ValueBool()
+	features := providerFeatures{}
+	if recreate, ok := boolValue(config.ApplicationRecreateOnUpgrade); ok {
+		features.ApplicationConflictStrategy = replacementStrategyFail
+		if recreate {
+			features.ApplicationConflictStrategy = replacementStrategyUpgradeForceRestart
+		}
 	}
 	if !config.AllowApplicationTypeUpdates.IsNull() && !config.AllowApplicationTypeUpdates.IsUnknown() {
 		features.AllowApplicationTypeUpdates = config.AllowApplicationTypeUpdates.ValueBool()
 	}
 	allowApplicationTypeUpdatesFlag.Store(features.AllowApplicationTypeUpdates)
 
+	defaults := applicationDefaults{UpgradePolicy: config.DefaultUpgradePolicy.upgradePolicy()}
+	if !config.DefaultApplicationParameters.IsNull() && !config.DefaultApplicationParameters.IsUnknown() {
+		resp.Diagnostics.Append(config.DefaultApplicationParameters.ElementsAs(ctx, &defaults.Parameters, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	providerData := &providerData{
-		Client:   client,
-		Features: features,
+		Client:              client,
+		Features:            features,
+		ApplicationDefaults: defaults,
+		Coordinator:         newOperationCoordinator(),
 	}
 
 	resp.DataSourceData = providerData
 	resp.ResourceData = providerData
+	resp.ListResourceData = providerData
 }
 
 func allowApplicationTypeUpdatesEnabled() bool {
@@ -276,6 +608,16 @@ func (p *serviceFabricProvider) Resources(_ context.Context) []func() resource.R
 		NewApplicationTypeResource,
 		NewApplicationResource,
 		NewServiceResource,
+		NewServiceGroupResource,
+	}
+}
+
+// ListResources returns the list resources implemented by the provider.
+func (p *serviceFabricProvider) ListResources(_ context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewApplicationTypeListResource,
+		NewApplicationListResource,
+		NewServiceListResource,
 	}
 }
 
@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	schemavalidator "github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// applicationDefaults are the provider-level settings every
+// servicefabric_application inherits. A resource's own settings take
+// precedence field by field.
+type applicationDefaults struct {
+	UpgradePolicy *upgradePolicyModel
+	Parameters    map[string]string
+}
+
+// defaultUpgradePolicyModel is the provider's default_upgrade_policy block:
+// upgrade_policy without recreate_application, which deletes application state
+// and so is only accepted on individual applications.
+type defaultUpgradePolicyModel struct {
+	ForceRestart                  types.Bool                    `tfsdk:"force_restart"`
+	UpgradeMode                   types.String                  `tfsdk:"upgrade_mode"`
+	UpgradeReplicaSetCheckTimeout types.Int64                   `tfsdk:"upgrade_replica_set_check_timeout_seconds"`
+	SortOrder                     types.String                  `tfsdk:"sort_order"`
+	InstanceCloseDelayDuration    types.Int64                   `tfsdk:"instance_close_delay_duration_seconds"`
+	MonitoringPolicy              *monitoringPolicyModel        `tfsdk:"monitoring_policy"`
+	ApplicationHealthPolicy       *applicationHealthPolicyModel `tfsdk:"application_health_policy"`
+}
+
+// upgradePolicy converts m to the model shared with servicefabric_application.
+func (m *defaultUpgradePolicyModel) upgradePolicy() *upgradePolicyModel {
+	if m == nil {
+		return nil
+	}
+	return &upgradePolicyModel{
+		ForceRestart:                  m.ForceRestart,
+		UpgradeMode:                   m.UpgradeMode,
+		UpgradeReplicaSetCheckTimeout: m.UpgradeReplicaSetCheckTimeout,
+		SortOrder:                     m.SortOrder,
+		InstanceCloseDelayDuration:    m.InstanceCloseDelayDuration,
+		RecreateApplication:           types.BoolNull(),
+		MonitoringPolicy:              m.MonitoringPolicy,
+		ApplicationHealthPolicy:       m.ApplicationHealthPolicy,
+	}
+}
+
+// mergeParameters returns the default parameters overlaid with own.
+func (d applicationDefaults) mergeParameters(own map[string]string) map[string]string {
+	merged := make(map[string]string, len(d.Parameters)+len(own))
+	for name, value := range d.Parameters {
+		merged[name] = value
+	}
+	for name, value := range own {
+		merged[name] = value
+	}
+	return merged
+}
+
+// mergeUpgradePolicy returns own with unset fields taken from the default
+// upgrade policy.
+func (d applicationDefaults) mergeUpgradePolicy(own *upgradePolicyModel) *upgradePolicyModel {
+	base := d.UpgradePolicy
+	if base == nil || own == nil {
+		if own != nil {
+			return own
+		}
+		return base
+	}
+	return &upgradePolicyModel{
+		ForceRestart:                  orDefault(own.ForceRestart, base.ForceRestart),
+		UpgradeMode:                   orDefault(own.UpgradeMode, base.UpgradeMode),
+		UpgradeReplicaSetCheckTimeout: orDefault(own.UpgradeReplicaSetCheckTimeout, base.UpgradeReplicaSetCheckTimeout),
+		SortOrder:                     orDefault(own.SortOrder, base.SortOrder),
+		InstanceCloseDelayDuration:    orDefault(own.InstanceCloseDelayDuration, base.InstanceCloseDelayDuration),
+		RecreateApplication:           own.RecreateApplication,
+		MonitoringPolicy:              mergeMonitoringPolicyModels(base.MonitoringPolicy, own.MonitoringPolicy),
+		ApplicationHealthPolicy:       mergeApplicationHealthPolicyModels(base.ApplicationHealthPolicy, own.ApplicationHealthPolicy),
+	}
+}
+
+func mergeMonitoringPolicyModels(base, own *monitoringPolicyModel) *monitoringPolicyModel {
+	if base == nil || own == nil {
+		if own != nil {
+			return own
+		}
+		return base
+	}
+	return &monitoringPolicyModel{
+		FailureAction:             orDefault(own.FailureAction, base.FailureAction),
+		HealthCheckWaitDuration:   orDefault(own.HealthCheckWaitDuration, base.HealthCheckWaitDuration),
+		HealthCheckStableDuration: orDefault(own.HealthCheckStableDuration, base.HealthCheckStableDuration),
+		HealthCheckRetryTimeout:   orDefault(own.HealthCheckRetryTimeout, base.HealthCheckRetryTimeout),
+		UpgradeTimeout:            orDefault(own.UpgradeTimeout, base.UpgradeTimeout),
+		UpgradeDomainTimeout:      orDefault(own.UpgradeDomainTimeout, base.UpgradeDomainTimeout),
+	}
+}
+
+// mergeApplicationHealthPolicyModels merges field by field; per-type policies
+// are merged by service type name, the resource's entry replacing the default.
+func mergeApplicationHealthPolicyModels(base, own *applicationHealthPolicyModel) *applicationHealthPolicyModel {
+	if base == nil || own == nil {
+		if own != nil {
+			return own
+		}
+		return base
+	}
+	merged := &applicationHealthPolicyModel{
+		ConsiderWarningAsError:                  orDefault(own.ConsiderWarningAsError, base.ConsiderWarningAsError),
+		MaxPercentUnhealthyDeployedApplications: orDefault(own.MaxPercentUnhealthyDeployedApplications, base.MaxPercentUnhealthyDeployedApplications),
+		DefaultServiceTypeHealthPolicy:          base.DefaultServiceTypeHealthPolicy,
+	}
+	if own.DefaultServiceTypeHealthPolicy != nil {
+		merged.DefaultServiceTypeHealthPolicy = own.DefaultServiceTypeHealthPolicy
+		if base.DefaultServiceTypeHealthPolicy != nil {
+			merged.DefaultServiceTypeHealthPolicy = &serviceTypeHealthPolicyModel{
+				MaxPercentUnhealthyServices:             orDefault(own.DefaultServiceTypeHealthPolicy.MaxPercentUnhealthyServices, base.DefaultServiceTypeHealthPolicy.MaxPercentUnhealthyServices),
+				MaxPercentUnhealthyPartitionsPerService: orDefault(own.DefaultServiceTypeHealthPolicy.MaxPercentUnhealthyPartitionsPerService, base.DefaultServiceTypeHealthPolicy.MaxPercentUnhealthyPartitionsPerService),
+				MaxPercentUnhealthyReplicasPerPartition: orDefault(own.DefaultServiceTypeHealthPolicy.MaxPercentUnhealthyReplicasPerPartition, base.DefaultServiceTypeHealthPolicy.MaxPercentUnhealthyReplicasPerPartition),
+			}
+		}
+	}
+	overridden := map[string]bool{}
+	for _, entry := range own.ServiceTypeHealthPolicies {
+		overridden[entry.ServiceTypeName.ValueString()] = true
+	}
+	for _, entry := range base.ServiceTypeHealthPolicies {
+		if !overridden[entry.ServiceTypeName.ValueString()] {
+			merged.ServiceTypeHealthPolicies = append(merged.ServiceTypeHealthPolicies, entry)
+		}
+	}
+	merged.ServiceTypeHealthPolicies = append(merged.ServiceTypeHealthPolicies, own.ServiceTypeHealthPolicies...)
+	return merged
+}
+
+func orDefault[T interface{ IsNull() bool }](own, base T) T {
+	if own.IsNull() {
+		return base
+	}
+	return own
+}
+
+// defaultUpgradePolicyBlock mirrors the upgrade_policy block of
+// servicefabric_application.
+func defaultUpgradePolicyBlock() providerschema.SingleNestedBlock {
+	durationAttribute := func(description string) providerschema.StringAttribute {
+		return providerschema.StringAttribute{
+			Optional:    true,
+			Description: description + " (ISO 8601 duration or milliseconds).",
+			Validators:  []schemavalidator.String{upgradeDurationValidator{}},
+		}
+	}
+	percentAttribute := func(description string) providerschema.Int64Attribute {
+		return providerschema.Int64Attribute{
+			Optional:    true,
+			Description: description,
+			Validators:  []schemavalidator.Int64{int64validator.Between(0, 100)},
+		}
+	}
+	serviceTypeAttributes := func() map[string]providerschema.Attribute {
+		return map[string]providerschema.Attribute{
+			"max_percent_unhealthy_services":               percentAttribute("Maximum percentage of unhealthy services of the type."),
+			"max_percent_unhealthy_partitions_per_service": percentAttribute("Maximum percentage of unhealthy partitions per service."),
+			"max_percent_unhealthy_replicas_per_partition": percentAttribute("Maximum percentage of unhealthy replicas per partition."),
+		}
+	}
+	perTypeAttributes := serviceTypeAttributes()
+	perTypeAttributes["service_type_name"] = providerschema.StringAttribute{
+		Required:    true,
+		Description: "Service type name the thresholds apply to.",
+	}
+
+	return providerschema.SingleNestedBlock{
+		Description: "Upgrade policy applied to every servicefabric_application. Settings in an application's own upgrade_policy take precedence field by field.",
+		Attributes: map[string]providerschema.Attribute{
+			"force_restart": providerschema.BoolAttribute{
+				Optional:    true,
+				Description: "Forcefully restart code packages during upgrades.",
+			},
+			"upgrade_mode": providerschema.StringAttribute{
+				Optional:    true,
+				Description: "Upgrade mode (UnmonitoredAuto, UnmonitoredManual, or Monitored).",
+				Validators: []schemavalidator.String{
+					stringvalidator.OneOf("UnmonitoredAuto", "UnmonitoredManual", "Monitored"),
+				},
+			},
+			"upgrade_replica_set_check_timeout_seconds": providerschema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum time, in seconds, to block processing of an upgrade domain to prevent loss of availability.",
+				Validators:  []schemavalidator.Int64{int64validator.AtLeast(0)},
+			},
+			"sort_order": providerschema.StringAttribute{
+				Optional:    true,
+				Description: "Order in which upgrade domains are upgraded (Default, Numeric, Lexicographical, ReverseNumeric, or ReverseLexicographical).",
+				Validators: []schemavalidator.String{
+					stringvalidator.OneOf("Default", "Numeric", "Lexicographical", "ReverseNumeric", "ReverseLexicographical"),
+				},
+			},
+			"instance_close_delay_duration_seconds": providerschema.Int64Attribute{
+				Optional:    true,
+				Description: "Seconds stateless instances with a configured delay wait before closing, so clients can drain.",
+				Validators:  []schemavalidator.Int64{int64validator.AtLeast(0)},
+			},
+		},
+		Blocks: map[string]providerschema.Block{
+			"monitoring_policy": providerschema.SingleNestedBlock{
+				Description: "Overrides monitoring timeouts for rolling upgrades.",
+				Attributes: map[string]providerschema.Attribute{
+					"failure_action": providerschema.StringAttribute{
+						Optional:    true,
+						Description: "Action taken when monitors report health violations. Allowed values: Rollback or Manual.",
+						Validators: []schemavalidator.String{
+							stringvalidator.OneOf("Rollback", "Manual"),
+						},
+					},
+					"health_check_wait_duration":   durationAttribute("Time to wait after completing an upgrade domain before health checks start"),
+					"health_check_stable_duration": durationAttribute("Time that health must remain stable before proceeding"),
+					"health_check_retry_timeout":   durationAttribute("Maximum time to wait for health to become stable before failure"),
+					"upgrade_timeout":              durationAttribute("Overall upgrade timeout"),
+					"upgrade_domain_timeout":       durationAttribute("Timeout per upgrade domain"),
+				},
+			},
+			"application_health_policy": providerschema.SingleNestedBlock{
+				Description: "Health policy evaluated during upgrades.",
+				Attributes: map[string]providerschema.Attribute{
+					"consider_warning_as_error": providerschema.BoolAttribute{
+						Optional:    true,
+						Description: "Treat warning health reports as errors during upgrades.",
+					},
+					"max_percent_unhealthy_deployed_applications": percentAttribute("Maximum percentage of unhealthy deployed applications allowed before aborting upgrades."),
+				},
+				Blocks: map[string]providerschema.Block{
+					"default_service_type_health_policy": providerschema.SingleNestedBlock{
+						Description: "Health thresholds for services whose type has no service_type_health_policy entry.",
+						Attributes:  serviceTypeAttributes(),
+					},
+					"service_type_health_policy": providerschema.ListNestedBlock{
+						Description: "Health thresholds for the services of one service type.",
+						NestedObject: providerschema.NestedBlockObject{
+							Attributes: perTypeAttributes,
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,297 @@
+package provider
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Provider settings are resolved in this order, the first source that sets a
+// value winning:
+//
+//  1. the provider block,
+//  2. environment variables,
+//  3. the sfctl connection profile, consulted only when neither of the above
+//     sets an endpoint or endpoints so that a profile never mixes with another cluster's
+//     configuration.
+//
+// Certificate settings are resolved as a group: once a higher-precedence
+// source selects an authentication method, certificate settings from lower
+// ones are ignored so they cannot switch methods or mix certificates.
+
+const (
+	sourceProviderConfig = "provider configuration"
+	sfctlProfileSection  = "servicefabric"
+)
+
+// providerStringEnv lists the environment variables read for string settings.
+var providerStringEnv = []struct {
+	attribute string
+	env       []string
+	field     func(*serviceFabricProviderModel) *types.String
+}{
+	{"endpoint", []string{"SF_ENDPOINT"}, func(m *serviceFabricProviderModel) *types.String { return &m.Endpoint }},
+	{"ca_certificate_path", []string{"SF_CA_CERTIFICATE_PATH"}, func(m *serviceFabricProviderModel) *types.String { return &m.CACertificatePath }},
+	{"client_certificate_path", []string{"SF_CLIENT_CERTIFICATE_PATH"}, func(m *serviceFabricProviderModel) *types.String { return &m.ClientCertificatePath }},
+	{"client_certificate", []string{"SF_CLIENT_CERTIFICATE"}, func(m *serviceFabricProviderModel) *types.String { return &m.ClientCertificate }},
+	{"client_certificate_key_path", []string{"SF_CLIENT_CERTIFICATE_KEY_PATH"}, func(m *serviceFabricProviderModel) *types.String { return &m.ClientCertificateKeyPath }},
+	{"client_certificate_password", []string{"SF_CLIENT_CERTIFICATE_PASSWORD"}, func(m *serviceFabricProviderModel) *types.String { return &m.ClientCertificatePassword }},
+	{"cluster_application_id", []string{"SF_CLUSTER_APPLICATION_ID"}, func(m *serviceFabricProviderModel) *types.String { return &m.ClusterApplicationID }},
+	{"tenant_id", []string{"SF_TENANT_ID", "ARM_TENANT_ID"}, func(m *serviceFabricProviderModel) *types.String { return &m.TenantID }},
+	{"client_id", []string{"SF_CLIENT_ID", "ARM_CLIENT_ID"}, func(m *serviceFabricProviderModel) *types.String { return &m.ClientID }},
+	{"client_secret", []string{"SF_CLIENT_SECRET", "ARM_CLIENT_SECRET"}, func(m *serviceFabricProviderModel) *types.String { return &m.ClientSecret }},
+	{"environment", []string{"SF_ENVIRONMENT", "ARM_ENVIRONMENT"}, func(m *serviceFabricProviderModel) *types.String { return &m.Environment }},
+	{"authority_host", []string{"SF_AUTHORITY_HOST", "AZURE_AUTHORITY_HOST"}, func(m *serviceFabricProviderModel) *types.String { return &m.AuthorityHost }},
+	{"oidc_token", []string{"SF_OIDC_TOKEN", "ARM_OIDC_TOKEN"}, func(m *serviceFabricProviderModel) *types.String { return &m.OIDCToken }},
+	{"oidc_token_file_path", []string{"SF_OIDC_TOKEN_FILE_PATH", "ARM_OIDC_TOKEN_FILE_PATH"}, func(m *serviceFabricProviderModel) *types.String { return &m.OIDCTokenFilePath }},
+}
+
+// credentialAttributes select an authentication method.
+var credentialAttributes = []string{"client_certificate_path", "client_certificate", "cluster_application_id"}
+
+// exclusiveSettings are alternatives for the same credential; a setting from a
+// lower-precedence source is ignored once one of its alternatives is set.
+var exclusiveSettings = map[string][]string{
+	"client_secret":        {"oidc_token", "oidc_token_file_path"},
+	"oidc_token":           {"client_secret", "oidc_token_file_path"},
+	"oidc_token_file_path": {"client_secret", "oidc_token"},
+}
+
+// providerSettingSources records where each resolved setting came from.
+type providerSettingSources map[string]string
+
+// logFields returns the sources in a form suitable for tflog.
+func (s providerSettingSources) logFields() map[string]any {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := make(map[string]any, len(names))
+	for _, name := range names {
+		fields[name] = s[name]
+	}
+	return fields
+}
+
+// describe appends the source of attribute to a diagnostic detail.
+func (s providerSettingSources) describe(attribute string) string {
+	if source, ok := s[attribute]; ok {
+		return fmt.Sprintf(" (%s set from %s)", attribute, source)
+	}
+	return ""
+}
+
+// resolveProviderSettings fills unset attributes of config from the
+// environment and the sfctl profile and reports the source of every setting.
+func resolveProviderSettings(config *serviceFabricProviderModel) (providerSettingSources, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	sources := providerSettingSources{}
+
+	for _, setting := range providerStringEnv {
+		if field := setting.field(config); !field.IsNull() && !field.IsUnknown() && field.ValueString() != "" {
+			sources[setting.attribute] = sourceProviderConfig
+		}
+	}
+	if !config.SkipTLSVerify.IsNull() {
+		sources["skip_tls_verify"] = sourceProviderConfig
+	}
+	if !config.Endpoints.IsNull() && len(config.Endpoints.Elements()) > 0 {
+		sources["endpoints"] = sourceProviderConfig
+	}
+
+	methodSelected := sources.anyOf(credentialAttributes...)
+	for _, setting := range providerStringEnv {
+		if _, ok := sources[setting.attribute]; ok {
+			continue
+		}
+		if setting.attribute == "endpoint" && sources.anyOf("endpoints") {
+			continue
+		}
+		if sources.anyOf(exclusiveSettings[setting.attribute]...) {
+			continue
+		}
+		if methodSelected && isCertificateSetting(setting.attribute) {
+			continue
+		}
+		if setting.attribute == "ca_certificate_path" && config.SkipTLSVerify.ValueBool() {
+			continue
+		}
+		for _, name := range setting.env {
+			if value := os.Getenv(name); value != "" {
+				*setting.field(config) = types.StringValue(value)
+				sources[setting.attribute] = "environment variable " + name
+				break
+			}
+		}
+	}
+	if _, ok := sources["skip_tls_verify"]; !ok {
+		if value := os.Getenv("SF_SKIP_TLS_VERIFY"); value != "" {
+			skip, err := strconv.ParseBool(value)
+			if err != nil {
+				diags.AddAttributeError(
+					path.Root("skip_tls_verify"),
+					"Invalid SF_SKIP_TLS_VERIFY value",
+					fmt.Sprintf("SF_SKIP_TLS_VERIFY must be a boolean, got %q.", value),
+				)
+				return sources, diags
+			}
+			config.SkipTLSVerify = types.BoolValue(skip)
+			sources["skip_tls_verify"] = "environment variable SF_SKIP_TLS_VERIFY"
+		}
+	}
+
+	if sources.anyOf("endpoint", "endpoints") {
+		return sources, diags
+	}
+
+	profilePath, explicit := sfctlProfilePath(config)
+	profile, err := readSfctlProfile(profilePath)
+	switch {
+	case err == nil:
+		diags.Append(applySfctlProfile(config, sources, profile, fmt.Sprintf("sfctl profile %s", profilePath))...)
+	case explicit || !os.IsNotExist(err):
+		diags.AddAttributeWarning(
+			path.Root("sfctl_config_path"),
+			"Unable to read sfctl profile",
+			fmt.Sprintf("The sfctl connection profile at %q could not be read: %s", profilePath, err),
+		)
+	}
+	return sources, diags
+}
+
+func (s providerSettingSources) anyOf(attributes ...string) bool {
+	for _, attribute := range attributes {
+		if _, ok := s[attribute]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func isCertificateSetting(attribute string) bool {
+	switch attribute {
+	case "client_certificate_path", "client_certificate", "client_certificate_key_path", "client_certificate_password":
+		return true
+	}
+	return false
+}
+
+// sfctlProfilePath returns the sfctl configuration file to read and whether it
+// was chosen explicitly.
+func sfctlProfilePath(config *serviceFabricProviderModel) (string, bool) {
+	if value := config.SfctlConfigPath.ValueString(); value != "" {
+		return value, true
+	}
+	if value := os.Getenv("SF_SFCTL_CONFIG_PATH"); value != "" {
+		return value, true
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".sfctl", "config"), false
+	}
+	return filepath.Join(home, ".sfctl", "config"), false
+}
+
+// readSfctlProfile reads the [servicefabric] section of the INI file written
+// by `sfctl cluster select`.
+func readSfctlProfile(name string) (map[string]string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := map[string]string{}
+	section := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "", strings.HasPrefix(line, "#"), strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			continue
+		}
+		if section != sfctlProfileSection {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			key, value, ok = strings.Cut(line, ":")
+		}
+		if ok {
+			values[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// applySfctlProfile maps sfctl's endpoint, security, cert_path, key_path,
+// pem_path, use_ca, ca_path and no_verify settings onto unset attributes.
+func applySfctlProfile(config *serviceFabricProviderModel, sources providerSettingSources, profile map[string]string, source string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	endpoint := profile["endpoint"]
+	if endpoint == "" {
+		return diags
+	}
+	config.Endpoint = types.StringValue(endpoint)
+	sources["endpoint"] = source
+	// The profile is read implicitly, so make the cluster it selects visible in
+	// every plan rather than only in the logs.
+	diags.AddAttributeWarning(
+		path.Root("endpoint"),
+		"Endpoint read from sfctl profile",
+		fmt.Sprintf("No endpoint is configured in the provider block or environment, so the cluster %s from the %s is used. Set endpoint or SF_ENDPOINT to target a cluster explicitly.", endpoint, source),
+	)
+
+	set := func(attribute string, field *types.String, value string) {
+		if value == "" || sources.anyOf(attribute) {
+			return
+		}
+		*field = types.StringValue(value)
+		sources[attribute] = source
+	}
+
+	if !sources.anyOf(credentialAttributes...) {
+		switch security := strings.ToLower(profile["security"]); security {
+		case "cert":
+			set("client_certificate_path", &config.ClientCertificatePath, profile["cert_path"])
+			set("client_certificate_key_path", &config.ClientCertificateKeyPath, profile["key_path"])
+		case "pem":
+			set("client_certificate_path", &config.ClientCertificatePath, profile["pem_path"])
+		case "", "none":
+		default:
+			diags.AddWarning(
+				"Unsupported sfctl security mode",
+				fmt.Sprintf("The %s selects security %q, which the provider cannot reuse. Configure credentials in the provider block or environment instead.", source, security),
+			)
+		}
+	}
+
+	if useCA, _ := strconv.ParseBool(profile["use_ca"]); useCA {
+		set("ca_certificate_path", &config.CACertificatePath, profile["ca_path"])
+	}
+	if noVerify, _ := strconv.ParseBool(profile["no_verify"]); noVerify && !sources.anyOf("skip_tls_verify", "ca_certificate_path") {
+		config.SkipTLSVerify = types.BoolValue(true)
+		sources["skip_tls_verify"] = source
+		diags.AddAttributeWarning(
+			path.Root("skip_tls_verify"),
+			"TLS verification disabled by sfctl profile",
+			fmt.Sprintf("The %s sets no_verify, so the server certificate of %s is not verified. Set skip_tls_verify = false or configure ca_certificate_path or server_certificate_thumbprints to verify it.", source, endpoint),
+		)
+	}
+	return diags
+}
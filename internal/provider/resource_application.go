@@ -6,15 +6,19 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	stringplanmodifier "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -23,6 +27,19 @@ import (
 
 var _ resource.Resource = &applicationResource{}
 var _ resource.ResourceWithImportState = &applicationResource{}
+var _ resource.ResourceWithIdentity = &applicationResource{}
+var _ resource.ResourceWithUpgradeState = &applicationResource{}
+var _ resource.ResourceWithModifyPlan = &applicationResource{}
+var _ resource.ResourceWithValidateConfig = &applicationResource{}
+
+// Replacement strategies decide what happens when an application has to change
+// in a way Service Fabric cannot apply in place, or already exists on create.
+const (
+	replacementStrategyUpgrade             = "upgrade"
+	replacementStrategyUpgradeForceRestart = "upgrade_force_restart"
+	replacementStrategyDeleteAndRecreate   = "delete_and_recreate"
+	replacementStrategyFail                = "fail"
+)
 
 var (
 	applicationMetricAttrTypes = map[string]attr.Type{
@@ -44,8 +61,10 @@ var (
 )
 
 type applicationResource struct {
-	client   *servicefabric.Client
-	features providerFeatures
+	client      *servicefabric.Client
+	features    providerFeatures
+	defaults    applicationDefaults
+	coordinator *operationCoordinator
 }
 
 type applicationResourceModel struct {
@@ -54,13 +73,33 @@ type applicationResourceModel struct {
 	TypeName                   types.String        `tfsdk:"type_name"`
 	TypeVersion                types.String        `tfsdk:"type_version"`
 	Parameters                 types.Map           `tfsdk:"parameters"`
+	EffectiveParameters        types.Map           `tfsdk:"effective_parameters"`
 	Status                     types.String        `tfsdk:"status"`
 	HealthState                types.String        `tfsdk:"health_state"`
 	ApplicationCapacity        types.Object        `tfsdk:"application_capacity"`
 	ManagedApplicationIdentity types.Object        `tfsdk:"managed_application_identity"`
+	ReplacementStrategy        types.String        `tfsdk:"replacement_strategy"`
+	DeletionProtection         types.Bool          `tfsdk:"deletion_protection"`
+	ForceRemove                types.Bool          `tfsdk:"force_remove"`
+	WaitForUpgrade             types.Bool          `tfsdk:"wait_for_upgrade"`
+	UpgradeState               types.String        `tfsdk:"upgrade_state"`
+	UpgradeDomainProgress      types.Map           `tfsdk:"upgrade_domain_progress"`
+	TargetTypeVersion          types.String        `tfsdk:"target_type_version"`
 	UpgradePolicy              *upgradePolicyModel `tfsdk:"upgrade_policy"`
 }
 
+// replacementStrategy returns the configured strategy, defaulting to upgrade.
+func (m applicationResourceModel) replacementStrategy() string {
+	if strategy, ok := stringValue(m.ReplacementStrategy); ok && strategy != "" {
+		return strategy
+	}
+	return replacementStrategyUpgrade
+}
+
+type applicationIdentityModel struct {
+	Name types.String `tfsdk:"name"`
+}
+
 type applicationCapacityModel struct {
 	MinimumNodes       types.Int64              `tfsdk:"minimum_nodes"`
 	MaximumNodes       types.Int64              `tfsdk:"maximum_nodes"`
@@ -80,17 +119,25 @@ type managedApplicationIdentityModel struct {
 }
 
 type upgradePolicyModel struct {
-	ForceRestart            types.Bool                    `tfsdk:"force_restart"`
-	UpgradeMode             types.String                  `tfsdk:"upgrade_mode"`
-	MonitoringPolicy        *monitoringPolicyModel        `tfsdk:"monitoring_policy"`
-	ApplicationHealthPolicy *applicationHealthPolicyModel `tfsdk:"application_health_policy"`
+	ForceRestart                  types.Bool                    `tfsdk:"force_restart"`
+	UpgradeMode                   types.String                  `tfsdk:"upgrade_mode"`
+	UpgradeReplicaSetCheckTimeout types.Int64                   `tfsdk:"upgrade_replica_set_check_timeout_seconds"`
+	SortOrder                     types.String                  `tfsdk:"sort_order"`
+	InstanceCloseDelayDuration    types.Int64                   `tfsdk:"instance_close_delay_duration_seconds"`
+	RecreateApplication           types.Bool                    `tfsdk:"recreate_application"`
+	MonitoringPolicy              *monitoringPolicyModel        `tfsdk:"monitoring_policy"`
+	ApplicationHealthPolicy       *applicationHealthPolicyModel `tfsdk:"application_health_policy"`
 }
 
 type applicationUpgradePolicy struct {
-	ForceRestart            *bool
-	UpgradeMode             string
-	MonitoringPolicy        *servicefabric.RollingUpgradeMonitoringPolicy
-	ApplicationHealthPolicy *servicefabric.ApplicationHealthPolicy
+	ForceRestart                  *bool
+	UpgradeMode                   string
+	UpgradeReplicaSetCheckTimeout *int64
+	SortOrder                     string
+	InstanceCloseDelayDuration    *int64
+	RecreateApplication           bool
+	MonitoringPolicy              *servicefabric.RollingUpgradeMonitoringPolicy
+	ApplicationHealthPolicy       *servicefabric.ApplicationHealthPolicy
 }
 
 type monitoringPolicyModel struct {
@@ -103,8 +150,23 @@ type monitoringPolicyModel struct {
 }
 
 type applicationHealthPolicyModel struct {
-	ConsiderWarningAsError                  types.Bool  `tfsdk:"consider_warning_as_error"`
-	MaxPercentUnhealthyDeployedApplications types.Int64 `tfsdk:"max_percent_unhealthy_deployed_applications"`
+	ConsiderWarningAsError                  types.Bool                        `tfsdk:"consider_warning_as_error"`
+	MaxPercentUnhealthyDeployedApplications types.Int64                       `tfsdk:"max_percent_unhealthy_deployed_applications"`
+	DefaultServiceTypeHealthPolicy          *serviceTypeHealthPolicyModel     `tfsdk:"default_service_type_health_policy"`
+	ServiceTypeHealthPolicies               []serviceTypeHealthPolicyMapModel `tfsdk:"service_type_health_policy"`
+}
+
+type serviceTypeHealthPolicyModel struct {
+	MaxPercentUnhealthyServices             types.Int64 `tfsdk:"max_percent_unhealthy_services"`
+	MaxPercentUnhealthyPartitionsPerService types.Int64 `tfsdk:"max_percent_unhealthy_partitions_per_service"`
+	MaxPercentUnhealthyReplicasPerPartition types.Int64 `tfsdk:"max_percent_unhealthy_replicas_per_partition"`
+}
+
+type serviceTypeHealthPolicyMapModel struct {
+	ServiceTypeName                         types.String `tfsdk:"service_type_name"`
+	MaxPercentUnhealthyServices             types.Int64  `tfsdk:"max_percent_unhealthy_services"`
+	MaxPercentUnhealthyPartitionsPerService types.Int64  `tfsdk:"max_percent_unhealthy_partitions_per_service"`
+	MaxPercentUnhealthyReplicasPerPartition types.Int64  `tfsdk:"max_percent_unhealthy_replicas_per_partition"`
 }
 
 func NewApplicationResource() resource.Resource {
@@ -115,8 +177,20 @@ func (r *applicationResource) Metadata(_ context.Context, req resource.MetadataR
 	resp.TypeName = req.ProviderTypeName + "_application"
 }
 
+func (r *applicationResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"name": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Fully-qualified Service Fabric application name, e.g. fabric:/MyApp.",
+			},
+		},
+	}
+}
+
 func (r *applicationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = rschema.Schema{
+		Version: 1,
 		Attributes: map[string]rschema.Attribute{
 			"id": rschema.StringAttribute{
 				Computed:      true,
@@ -140,6 +214,11 @@ func (r *applicationResource) Schema(_ context.Context, _ resource.SchemaRequest
 				ElementType: types.StringType,
 				Description: "Application parameters supplied to the deployment.",
 			},
+			"effective_parameters": rschema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Parameters deployed to the application: the provider's default_application_parameters overlaid with parameters.",
+			},
 			"application_capacity": rschema.SingleNestedAttribute{
 				Optional:    true,
 				Description: "Application capacity settings used to reserve and limit cluster resources.",
@@ -193,6 +272,47 @@ func (r *applicationResource) Schema(_ context.Context, _ resource.SchemaRequest
 					},
 				},
 			},
+			"replacement_strategy": rschema.StringAttribute{
+				Optional: true,
+				Description: "How changes that need more than an in-place update are applied. Unset, a create that finds the application already exists fails, so an unmanaged application is only taken over when a strategy is set explicitly; " +
+					"otherwise upgrade is the default. upgrade runs a rolling upgrade, upgrade_force_restart runs one that restarts code packages, " +
+					"delete_and_recreate deletes the application and creates it again, and fail rejects the change. Applies when type_version, parameters or managed identities change, " +
+					"when name, type_name or application_capacity change or all managed identities are removed (which only delete_and_recreate can apply), and when create finds the application already exists.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(replacementStrategyUpgrade, replacementStrategyUpgradeForceRestart, replacementStrategyDeleteAndRecreate, replacementStrategyFail),
+				},
+			},
+			"deletion_protection": rschema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "When true, destroying or replacing the application fails. Set to false and apply before destroying it.",
+			},
+			"force_remove": rschema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Forcefully delete the application without graceful shutdown of its services.",
+			},
+			"wait_for_upgrade": rschema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Wait for upgrades to complete. When false, updates start the upgrade and return; the next plan reports its progress.",
+			},
+			"upgrade_state": rschema.StringAttribute{
+				Computed:    true,
+				Description: "State of the most recent upgrade, for example RollingForwardInProgress or RollingBackCompleted.",
+			},
+			"upgrade_domain_progress": rschema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "State of each upgrade domain in the most recent upgrade, keyed by upgrade domain name.",
+			},
+			"target_type_version": rschema.StringAttribute{
+				Computed:    true,
+				Description: "Application type version the most recent upgrade targets.",
+			},
 			"status": rschema.StringAttribute{
 				Computed:    true,
 				Description: "Current application status.",
@@ -217,6 +337,31 @@ func (r *applicationResource) Schema(_ context.Context, _ resource.SchemaRequest
 							stringvalidator.OneOf("UnmonitoredAuto", "UnmonitoredManual", "Monitored"),
 						},
 					},
+					"upgrade_replica_set_check_timeout_seconds": rschema.Int64Attribute{
+						Optional:    true,
+						Description: "Maximum time, in seconds, to block processing of an upgrade domain to prevent loss of availability.",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"sort_order": rschema.StringAttribute{
+						Optional:    true,
+						Description: "Order in which upgrade domains are upgraded (Default, Numeric, Lexicographical, ReverseNumeric, or ReverseLexicographical).",
+						Validators: []validator.String{
+							stringvalidator.OneOf("Default", "Numeric", "Lexicographical", "ReverseNumeric", "ReverseLexicographical"),
+						},
+					},
+					"instance_close_delay_duration_seconds": rschema.Int64Attribute{
+						Optional:    true,
+						Description: "Seconds stateless instances with a configured delay wait before closing, so clients can drain.",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"recreate_application": rschema.BoolAttribute{
+						Optional:    true,
+						Description: "Delete and recreate the application during the upgrade instead of rolling it, losing all service state. No other upgrade policy settings may be set.",
+					},
 				},
 				Blocks: map[string]rschema.Block{
 					"monitoring_policy": rschema.SingleNestedBlock{
@@ -231,23 +376,38 @@ func (r *applicationResource) Schema(_ context.Context, _ resource.SchemaRequest
 							},
 							"health_check_wait_duration": rschema.StringAttribute{
 								Optional:    true,
-								Description: "Time to wait after completing an upgrade domain before health checks start (ISO8601 duration).",
+								Description: "Time to wait after completing an upgrade domain before health checks start (ISO 8601 duration or milliseconds).",
+								Validators: []validator.String{
+									upgradeDurationValidator{},
+								},
 							},
 							"health_check_stable_duration": rschema.StringAttribute{
 								Optional:    true,
-								Description: "Time that health must remain stable before proceeding (ISO8601 duration).",
+								Description: "Time that health must remain stable before proceeding (ISO 8601 duration or milliseconds).",
+								Validators: []validator.String{
+									upgradeDurationValidator{},
+								},
 							},
 							"health_check_retry_timeout": rschema.StringAttribute{
 								Optional:    true,
-								Description: "Maximum time to wait for health to become stable before failure (ISO8601 duration).",
+								Description: "Maximum time to wait for health to become stable before failure (ISO 8601 duration or milliseconds).",
+								Validators: []validator.String{
+									upgradeDurationValidator{},
+								},
 							},
 							"upgrade_timeout": rschema.StringAttribute{
 								Optional:    true,
-								Description: "Overall upgrade timeout (ISO8601 duration).",
+								Description: "Overall upgrade timeout (ISO 8601 duration or milliseconds).",
+								Validators: []validator.String{
+									upgradeDurationValidator{},
+								},
 							},
 							"upgrade_domain_timeout": rschema.StringAttribute{
 								Optional:    true,
-								Description: "Timeout per upgrade domain (ISO8601 duration).",
+								Description: "Timeout per upgrade domain (ISO 8601 duration or milliseconds).",
+								Validators: []validator.String{
+									upgradeDurationValidator{},
+								},
 							},
 						},
 					},
@@ -261,6 +421,26 @@ func (r *applicationResource) Schema(_ context.Context, _ resource.SchemaRequest
 							"max_percent_unhealthy_deployed_applications": rschema.Int64Attribute{
 								Optional:    true,
 								Description: "Maximum percentage of unhealthy deployed applications allowed before aborting upgrades.",
+								Validators:  percentValidators(),
+							},
+						},
+						Blocks: map[string]rschema.Block{
+							"default_service_type_health_policy": rschema.SingleNestedBlock{
+								Description: "Health thresholds for services whose type has no service_type_health_policy entry.",
+								Attributes:  serviceTypeHealthPolicyAttributes(),
+							},
+							"service_type_health_policy": rschema.ListNestedBlock{
+								Description: "Health thresholds for the services of one service type.",
+								NestedObject: rschema.NestedBlockObject{
+									Attributes: func() map[string]rschema.Attribute {
+										attributes := serviceTypeHealthPolicyAttributes()
+										attributes["service_type_name"] = rschema.StringAttribute{
+											Required:    true,
+											Description: "Service type name the thresholds apply to.",
+										}
+										return attributes
+									}(),
+								},
 							},
 						},
 					},
@@ -270,6 +450,64 @@ func (r *applicationResource) Schema(_ context.Context, _ resource.SchemaRequest
 	}
 }
 
+// serviceTypeHealthPolicyAttributes returns the thresholds shared by the
+// default and per-type service health policies.
+func serviceTypeHealthPolicyAttributes() map[string]rschema.Attribute {
+	return map[string]rschema.Attribute{
+		"max_percent_unhealthy_services": rschema.Int64Attribute{
+			Optional:    true,
+			Description: "Maximum percentage of unhealthy services of the type.",
+			Validators:  percentValidators(),
+		},
+		"max_percent_unhealthy_partitions_per_service": rschema.Int64Attribute{
+			Optional:    true,
+			Description: "Maximum percentage of unhealthy partitions per service.",
+			Validators:  percentValidators(),
+		},
+		"max_percent_unhealthy_replicas_per_partition": rschema.Int64Attribute{
+			Optional:    true,
+			Description: "Maximum percentage of unhealthy replicas per partition.",
+			Validators:  percentValidators(),
+		},
+	}
+}
+
+func percentValidators() []validator.Int64 {
+	return []validator.Int64{int64validator.Between(0, 100)}
+}
+
+// UpgradeState migrates version 0 states, which could hold the bare application
+// name as identifier after import, to the "{type_name}|{name}" format.
+func (r *applicationResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			StateUpgrader: func(_ context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				upgradeRawState(req, resp, func(attrs map[string]any) {
+					id, _ := attrs["id"].(string)
+					typeName, _ := attrs["type_name"].(string)
+					name, _ := attrs["name"].(string)
+					if idType, idName, ok := splitApplicationCompositeID(id); ok {
+						if typeName == "" {
+							typeName = idType
+							attrs["type_name"] = typeName
+						}
+						if name == "" {
+							name = idName
+							attrs["name"] = name
+						}
+					} else if name == "" {
+						name = id
+						attrs["name"] = name
+					}
+					if name != "" {
+						attrs["id"] = applicationCompositeID(typeName, name)
+					}
+				})
+			},
+		},
+	}
+}
+
 func (r *applicationResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -280,6 +518,469 @@ func (r *applicationResource) Configure(_ context.Context, req resource.Configur
 	}
 	r.client = data.Client
 	r.features = data.Features
+	r.defaults = data.ApplicationDefaults
+	r.coordinator = data.Coordinator
+}
+
+func (r *applicationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		checkDeletionProtection(ctx, req.State, &resp.Diagnostics, "application")
+		return
+	}
+	var plan applicationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var uses []clusterFeatureUse
+	if !plan.ManagedApplicationIdentity.IsNull() {
+		uses = append(uses, clusterFeatureUse{path.Root("managed_application_identity"), servicefabric.FeatureManagedApplicationIdentity})
+	}
+	resp.Diagnostics.Append(checkClusterFeatures(r.client, uses)...)
+
+	var state *applicationResourceModel
+	if !req.State.Raw.IsNull() {
+		state = &applicationResourceModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// effective_parameters follows the configuration alone: removing the
+	// parameters block deploys only the provider defaults.
+	own := plan.Parameters
+	plan.EffectiveParameters = types.MapUnknown(types.StringType)
+	if !own.IsUnknown() {
+		ownParams := map[string]string{}
+		if !own.IsNull() {
+			resp.Diagnostics.Append(own.ElementsAs(ctx, &ownParams, false)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+		plan.EffectiveParameters = types.MapValueMust(types.StringType, convertStringMapToAttrValues(r.defaults.mergeParameters(ownParams)))
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("effective_parameters"), plan.EffectiveParameters)...)
+
+	r.checkApplicationTypeVersion(ctx, plan, state, resp)
+	if state == nil {
+		r.checkExistingApplication(ctx, plan, resp)
+		return
+	}
+	reportUpgradeProgress(ctx, plan, *state, resp)
+	r.planApplicationChange(ctx, plan, *state, resp)
+}
+
+// reportUpgradeProgress explains the drift left by an upgrade that has not
+// rolled out: one still in flight, typically started with wait_for_upgrade =
+// false, or one that rolled back or failed.
+func reportUpgradeProgress(ctx context.Context, plan, state applicationResourceModel, resp *resource.ModifyPlanResponse) {
+	upgradeState := state.UpgradeState.ValueString()
+	target := state.TargetTypeVersion.ValueString()
+
+	switch {
+	case servicefabric.IsUpgradeInProgress(upgradeState):
+		domains := map[string]string{}
+		if !state.UpgradeDomainProgress.IsNull() && !state.UpgradeDomainProgress.IsUnknown() {
+			resp.Diagnostics.Append(state.UpgradeDomainProgress.ElementsAs(ctx, &domains, false)...)
+		}
+		completed := 0
+		for _, domainState := range domains {
+			if domainState == "Completed" {
+				completed++
+			}
+		}
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("type_version"),
+			"Application upgrade in progress",
+			fmt.Sprintf("The upgrade of %s to type version %s is %s, with %d of %d upgrade domains completed. type_version and parameters show what the cluster runs until the upgrade completes. Applying follows the running upgrade instead of starting a new one.",
+				state.Name.ValueString(), target, upgradeState, completed, len(domains)),
+		)
+	case upgradeState == "RollingBackCompleted" || upgradeState == "Failed":
+		if target == "" || target != plan.TypeVersion.ValueString() {
+			return
+		}
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("type_version"),
+			"Application upgrade did not complete",
+			fmt.Sprintf("The upgrade of %s to type version %s ended in state %s, and the cluster still runs type version %s. Applying starts the upgrade again.",
+				state.Name.ValueString(), target, upgradeState, state.TypeVersion.ValueString()),
+		)
+	}
+}
+
+func (r *applicationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config applicationResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	policy := config.UpgradePolicy
+	if policy == nil {
+		return
+	}
+	if strategy, ok := stringValue(config.ReplacementStrategy); ok && strategy == replacementStrategyUpgradeForceRestart {
+		if forceRestart, ok := boolValue(policy.ForceRestart); ok && !forceRestart {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("upgrade_policy").AtName("force_restart"),
+				"Conflicting force_restart setting",
+				"replacement_strategy = \"upgrade_force_restart\" always restarts code packages; remove upgrade_policy.force_restart or choose replacement_strategy = \"upgrade\".",
+			)
+		}
+	}
+
+	if recreate, ok := boolValue(policy.RecreateApplication); !ok || !recreate {
+		return
+	}
+	for _, setting := range []struct {
+		name string
+		set  bool
+	}{
+		{"force_restart", !policy.ForceRestart.IsNull()},
+		{"upgrade_mode", !policy.UpgradeMode.IsNull()},
+		{"upgrade_replica_set_check_timeout_seconds", !policy.UpgradeReplicaSetCheckTimeout.IsNull()},
+		{"sort_order", !policy.SortOrder.IsNull()},
+		{"instance_close_delay_duration_seconds", !policy.InstanceCloseDelayDuration.IsNull()},
+		{"monitoring_policy", policy.MonitoringPolicy != nil},
+		{"application_health_policy", policy.ApplicationHealthPolicy != nil},
+	} {
+		if setting.set {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("upgrade_policy").AtName(setting.name),
+				"Conflicting upgrade policy settings",
+				fmt.Sprintf("upgrade_policy.recreate_application recreates the application instead of rolling it, so upgrade_policy.%s cannot be set with it.", setting.name),
+			)
+		}
+	}
+}
+
+// createConflictStrategy returns the strategy applied when the application
+// already exists on create. Taking over an application Terraform does not
+// manage must be opted into, so without replacement_strategy or the deprecated
+// provider flag the create fails.
+func (r *applicationResource) createConflictStrategy(plan applicationResourceModel) string {
+	if strategy, ok := stringValue(plan.ReplacementStrategy); ok && strategy != "" {
+		return strategy
+	}
+	if r.features.ApplicationConflictStrategy != "" {
+		return r.features.ApplicationConflictStrategy
+	}
+	return replacementStrategyFail
+}
+
+// checkExistingApplication reports at plan time what create will do with an
+// application of the same name that already exists in the cluster.
+func (r *applicationResource) checkExistingApplication(ctx context.Context, plan applicationResourceModel, resp *resource.ModifyPlanResponse) {
+	if r.client == nil {
+		return
+	}
+	name, ok := stringValue(plan.Name)
+	if !ok {
+		return
+	}
+	existing, err := r.client.GetApplication(ctx, name)
+	if err != nil {
+		if !servicefabric.IsNotFoundError(err) {
+			tflog.Debug(ctx, "Unable to check for an existing application", map[string]any{"name": name, "error": err.Error()})
+		}
+		return
+	}
+
+	summary := fmt.Sprintf("Application %s already exists with type %s version %s.", name, existing.TypeName, existing.TypeVersion)
+	switch strategy := r.createConflictStrategy(plan); strategy {
+	case replacementStrategyFail:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Application already exists",
+			summary+" Import it with terraform import, or set replacement_strategy explicitly to take it over.",
+		)
+	case replacementStrategyDeleteAndRecreate:
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("name"),
+			"Existing application will be deleted and recreated",
+			summary+" Apply deletes it, including its services and their state, and creates it again.",
+		)
+	default:
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("name"),
+			"Existing application will be upgraded",
+			fmt.Sprintf("%s Apply upgrades it to version %s %s instead of creating it.", summary, plan.TypeVersion.ValueString(), describeUpgrade(r.defaults.mergeUpgradePolicy(plan.UpgradePolicy), strategy)),
+		)
+	}
+}
+
+// planApplicationChange reports which operation apply runs for changes to an
+// existing application. Under delete_and_recreate the changed attributes force
+// replacement; otherwise attributes Service Fabric cannot update in place are
+// rejected, as are all upgrades under fail.
+func (r *applicationResource) planApplicationChange(ctx context.Context, plan, state applicationResourceModel, resp *resource.ModifyPlanResponse) {
+	strategy := plan.replacementStrategy()
+	immutable, diags := changedImmutableAttributes(ctx, plan, state, strategy == replacementStrategyDeleteAndRecreate)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+	versionChanged := plan.TypeVersion.IsUnknown() || plan.TypeVersion.ValueString() != state.TypeVersion.ValueString()
+	parametersChanged, diags := applicationParametersChanged(ctx, plan, state)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+	identityChanged, diags := managedIdentityChanged(ctx, plan, state)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+	name := state.Name.ValueString()
+
+	if strategy == replacementStrategyDeleteAndRecreate && (len(immutable) > 0 || versionChanged) {
+		if versionChanged {
+			immutable = append(immutable, path.Root("type_version"))
+		}
+		resp.RequiresReplace = append(resp.RequiresReplace, immutable...)
+		if state.DeletionProtection.ValueBool() {
+			resp.Diagnostics.AddError(
+				"Deletion protection enabled",
+				fmt.Sprintf("Applying this change deletes and recreates application %s, which has deletion_protection enabled. Set deletion_protection = false and apply that change first.", name),
+			)
+			return
+		}
+		resp.Diagnostics.AddWarning(
+			"Application will be deleted and recreated",
+			fmt.Sprintf("replacement_strategy is delete_and_recreate: apply deletes application %s, including its services and their state, and creates it again.", name),
+		)
+		return
+	}
+	for _, attribute := range immutable {
+		resp.Diagnostics.AddAttributeError(
+			attribute,
+			"Attribute cannot be changed in place",
+			fmt.Sprintf("Service Fabric cannot change %s on an existing application. Set replacement_strategy = \"delete_and_recreate\" to delete and recreate application %s, or revert the change.", attribute, name),
+		)
+	}
+	if len(immutable) > 0 || (!versionChanged && !parametersChanged && !identityChanged) {
+		return
+	}
+
+	var changes []string
+	attribute := path.Root("type_version")
+	if identityChanged {
+		changes = append(changes, "managed identities")
+		attribute = path.Root("managed_application_identity")
+	}
+	if parametersChanged {
+		changes = append([]string{"parameters"}, changes...)
+		attribute = path.Root("parameters")
+	}
+	if versionChanged {
+		attribute = path.Root("type_version")
+	}
+
+	if strategy == replacementStrategyFail {
+		resp.Diagnostics.AddAttributeError(
+			attribute,
+			"Application upgrade not allowed",
+			fmt.Sprintf("replacement_strategy is fail, so application %s is not upgraded. Change replacement_strategy to apply this change.", name),
+		)
+		return
+	}
+	if versionChanged {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("type_version"),
+			"Application will be upgraded",
+			fmt.Sprintf("Apply upgrades application %s from version %s to %s %s.", name, state.TypeVersion.ValueString(), describePlannedVersion(plan.TypeVersion), describeUpgrade(r.defaults.mergeUpgradePolicy(plan.UpgradePolicy), strategy)),
+		)
+		return
+	}
+	resp.Diagnostics.AddAttributeWarning(
+		attribute,
+		"Application will be upgraded",
+		fmt.Sprintf("Apply upgrades application %s at version %s to change its %s %s.", name, state.TypeVersion.ValueString(), strings.Join(changes, " and "), describeUpgrade(r.defaults.mergeUpgradePolicy(plan.UpgradePolicy), strategy)),
+	)
+}
+
+// changedImmutableAttributes returns the attributes Service Fabric cannot
+// update in place whose planned value differs from state; managed identities
+// count only when all of them are removed. Values not yet known count as
+// changed only when includeUnknown is set.
+func changedImmutableAttributes(ctx context.Context, plan, state applicationResourceModel, includeUnknown bool) (path.Paths, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var changed path.Paths
+	for _, attribute := range []struct {
+		name         string
+		planned, old types.String
+	}{
+		{"name", plan.Name, state.Name},
+		{"type_name", plan.TypeName, state.TypeName},
+	} {
+		if attribute.planned.IsUnknown() {
+			if includeUnknown {
+				changed = append(changed, path.Root(attribute.name))
+			}
+			continue
+		}
+		if attribute.planned.ValueString() != attribute.old.ValueString() {
+			changed = append(changed, path.Root(attribute.name))
+		}
+	}
+
+	if plan.ApplicationCapacity.IsUnknown() {
+		if includeUnknown {
+			changed = append(changed, path.Root("application_capacity"))
+		}
+	} else {
+		planCapacity, planDiags := expandApplicationCapacity(ctx, plan.ApplicationCapacity)
+		diags.Append(planDiags...)
+		stateCapacity, stateDiags := expandApplicationCapacity(ctx, state.ApplicationCapacity)
+		diags.Append(stateDiags...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		if !applicationCapacityEqual(planCapacity, stateCapacity) {
+			changed = append(changed, path.Root("application_capacity"))
+		}
+	}
+
+	if plan.ManagedApplicationIdentity.IsUnknown() {
+		if includeUnknown {
+			changed = append(changed, path.Root("managed_application_identity"))
+		}
+	} else {
+		planIdentity, planDiags := expandManagedApplicationIdentity(ctx, plan.ManagedApplicationIdentity)
+		diags.Append(planDiags...)
+		stateIdentity, stateDiags := expandManagedApplicationIdentity(ctx, state.ManagedApplicationIdentity)
+		diags.Append(stateDiags...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		if planIdentity == nil && stateIdentity != nil {
+			changed = append(changed, path.Root("managed_application_identity"))
+		}
+	}
+	return changed, diags
+}
+
+// managedIdentityChanged reports whether the planned managed identities differ
+// from state. Changes other than removing them all are applied by an upgrade.
+func managedIdentityChanged(ctx context.Context, plan, state applicationResourceModel) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if plan.ManagedApplicationIdentity.IsUnknown() {
+		return true, diags
+	}
+	planIdentity, planDiags := expandManagedApplicationIdentity(ctx, plan.ManagedApplicationIdentity)
+	diags.Append(planDiags...)
+	stateIdentity, stateDiags := expandManagedApplicationIdentity(ctx, state.ManagedApplicationIdentity)
+	diags.Append(stateDiags...)
+	if diags.HasError() {
+		return false, diags
+	}
+	return !managedApplicationIdentityEqual(planIdentity, stateIdentity), diags
+}
+
+// applicationParametersChanged reports whether the planned effective
+// parameters differ from those deployed. Parameters not yet known are not
+// reported.
+func applicationParametersChanged(ctx context.Context, plan, state applicationResourceModel) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if plan.EffectiveParameters.IsNull() || plan.EffectiveParameters.IsUnknown() {
+		return false, diags
+	}
+	planParams := map[string]string{}
+	diags.Append(plan.EffectiveParameters.ElementsAs(ctx, &planParams, false)...)
+	stateParams, stateDiags := deployedParameters(ctx, state)
+	diags.Append(stateDiags...)
+	if diags.HasError() {
+		return false, diags
+	}
+	return !stringMapEqual(planParams, stateParams), diags
+}
+
+// deployedParameters returns the parameters recorded in state as deployed,
+// falling back to parameters for states written before effective_parameters.
+func deployedParameters(ctx context.Context, state applicationResourceModel) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	params := map[string]string{}
+	value := state.EffectiveParameters
+	if value.IsNull() || value.IsUnknown() {
+		value = state.Parameters
+	}
+	if !value.IsNull() && !value.IsUnknown() {
+		diags.Append(value.ElementsAs(ctx, &params, false)...)
+	}
+	return params, diags
+}
+
+func describePlannedVersion(version types.String) string {
+	if version.IsUnknown() {
+		return "a version known after apply"
+	}
+	return version.ValueString()
+}
+
+// describeUpgrade states how the planned upgrade runs, following the same
+// rules as applyUpgradePolicy.
+func describeUpgrade(policy *upgradePolicyModel, strategy string) string {
+	forceRestart := strategy == replacementStrategyUpgradeForceRestart
+	if policy != nil {
+		if recreate, ok := boolValue(policy.RecreateApplication); ok && recreate {
+			return "by recreating it (upgrade_policy.recreate_application), which loses all service state"
+		}
+		if value, ok := boolValue(policy.ForceRestart); ok {
+			forceRestart = value
+		}
+	}
+	if forceRestart {
+		return "as a rolling upgrade with ForceRestart"
+	}
+	return "as a rolling upgrade without ForceRestart"
+}
+
+// checkApplicationTypeVersion fails the plan when the targeted application type
+// version is not provisioned, instead of failing during apply. Versions that
+// are still unknown come from an application type created in the same plan
+// and are not checked; neither are versions the application already runs.
+func (r *applicationResource) checkApplicationTypeVersion(ctx context.Context, plan applicationResourceModel, state *applicationResourceModel, resp *resource.ModifyPlanResponse) {
+	if r.client == nil {
+		return
+	}
+	typeName, ok := stringValue(plan.TypeName)
+	if !ok {
+		return
+	}
+	typeVersion, ok := stringValue(plan.TypeVersion)
+	if !ok {
+		return
+	}
+	if state != nil && state.TypeName.ValueString() == typeName && state.TypeVersion.ValueString() == typeVersion {
+		return
+	}
+
+	info, err := r.client.GetApplicationTypeVersion(ctx, typeName, typeVersion)
+	if err != nil {
+		if servicefabric.IsNotFoundError(err) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("type_version"),
+				"Application type version not provisioned",
+				fmt.Sprintf("Application type %s version %s is not provisioned in the cluster. Provision it first, for example with a servicefabric_application_type resource referenced by this application.", typeName, typeVersion),
+			)
+			return
+		}
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("type_version"),
+			"Unable to verify application type version",
+			fmt.Sprintf("Looking up application type %s version %s failed: %s", typeName, typeVersion, err),
+		)
+		return
+	}
+	if !strings.EqualFold(info.Status, "Available") {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("type_version"),
+			"Application type version not available",
+			fmt.Sprintf("Application type %s version %s has status %q. Creating or upgrading the application fails unless it becomes Available before apply.", typeName, typeVersion, info.Status),
+		)
+	}
 }
 
 func (r *applicationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -298,6 +999,7 @@ func (r *applicationResource) Create(ctx context.Context, req resource.CreateReq
 		}
 	}
 
+	paramMap = r.defaults.mergeParameters(paramMap)
 	desc := servicefabric.ApplicationDescription{
 		Name:         plan.Name.ValueString(),
 		TypeName:     plan.TypeName.ValueString(),
@@ -323,32 +1025,72 @@ func (r *applicationResource) Create(ctx context.Context, req resource.CreateReq
 		desc.ManagedApplicationIdentity = identityDesc
 	}
 
-	upgradePolicy, policyDiags := expandApplicationUpgradePolicy(ctx, plan.UpgradePolicy)
+	upgradePolicy, policyDiags := expandApplicationUpgradePolicy(ctx, r.defaults.mergeUpgradePolicy(plan.UpgradePolicy))
 	resp.Diagnostics.Append(policyDiags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	unlock, err := r.coordinator.lockApplication(ctx, plan.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create application", err.Error())
+		return
+	}
+	defer unlock()
+
+	wait := true
+	if v, ok := boolValue(plan.WaitForUpgrade); ok {
+		wait = v
+	}
+	upgradeStarted := false
 	if err := r.client.CreateApplication(ctx, desc); err != nil {
-		if r.features.ApplicationRecreateOnUpgrade && servicefabric.IsApplicationAlreadyExistsError(err) {
-			tflog.Info(ctx, "Existing Service Fabric application detected, initiating upgrade instead of create", map[string]any{
-				"name":         plan.Name.ValueString(),
-				"type_name":    plan.TypeName.ValueString(),
-				"type_version": plan.TypeVersion.ValueString(),
-			})
+		if !servicefabric.IsApplicationAlreadyExistsError(err) {
+			resp.Diagnostics.AddError("Failed to create application", err.Error())
+			return
+		}
+		strategy := r.createConflictStrategy(plan)
+		logFields := map[string]any{
+			"name":         plan.Name.ValueString(),
+			"type_name":    plan.TypeName.ValueString(),
+			"type_version": plan.TypeVersion.ValueString(),
+			"strategy":     strategy,
+		}
+		switch strategy {
+		case replacementStrategyFail:
+			resp.Diagnostics.AddError(
+				"Application already exists",
+				fmt.Sprintf("Application %s already exists. Import it with terraform import, or set replacement_strategy explicitly to take it over.", plan.Name.ValueString()),
+			)
+			return
+		case replacementStrategyDeleteAndRecreate:
+			tflog.Info(ctx, "Existing Service Fabric application detected, deleting it before create", logFields)
+			forceDelete, _ := boolValue(plan.ForceRemove)
+			if deleteErr := r.client.DeleteApplication(ctx, plan.Name.ValueString(), forceDelete); deleteErr != nil && !servicefabric.IsNotFoundError(deleteErr) {
+				resp.Diagnostics.AddError("Failed to delete existing application", deleteErr.Error())
+				return
+			}
+			if createErr := r.client.CreateApplication(ctx, desc); createErr != nil {
+				resp.Diagnostics.AddError("Failed to create application", createErr.Error())
+				return
+			}
+		default:
+			tflog.Info(ctx, "Existing Service Fabric application detected, initiating upgrade instead of create", logFields)
 			upgradeDesc := servicefabric.ApplicationUpgradeDescription{
 				Name:                         plan.Name.ValueString(),
 				TargetApplicationTypeVersion: plan.TypeVersion.ValueString(),
 				ParameterMap:                 paramMap,
+				ManagedApplicationIdentity:   identityDesc,
 			}
-			applyUpgradePolicy(&upgradeDesc, upgradePolicy, true)
-			if upgradeErr := r.client.UpgradeApplication(ctx, upgradeDesc); upgradeErr != nil {
+			applyUpgradePolicy(&upgradeDesc, upgradePolicy, strategy == replacementStrategyUpgradeForceRestart)
+			upgrade := r.client.UpgradeApplication
+			if !wait {
+				upgrade = r.client.StartApplicationUpgrade
+			}
+			if upgradeErr := upgrade(ctx, upgradeDesc); upgradeErr != nil {
 				resp.Diagnostics.AddError("Failed to upgrade existing application", upgradeErr.Error())
 				return
 			}
-		} else {
-			resp.Diagnostics.AddError("Failed to create application", err.Error())
-			return
+			upgradeStarted = !wait
 		}
 	}
 
@@ -360,12 +1102,22 @@ func (r *applicationResource) Create(ctx context.Context, req resource.CreateReq
 
 	plan.ID = types.StringValue(applicationCompositeID(plan.TypeName.ValueString(), plan.Name.ValueString()))
 
+	planned := plan
 	if err := r.refreshState(ctx, &plan); err != nil {
 		resp.Diagnostics.AddError("Failed to read application", err.Error())
 		return
 	}
+	if upgradeStarted {
+		// As in Update, record what was requested rather than the deployment
+		// the upgrade is still replacing.
+		plan.TypeVersion = planned.TypeVersion
+		plan.Parameters = planned.Parameters
+		plan.EffectiveParameters = types.MapValueMust(types.StringType, convertStringMapToAttrValues(paramMap))
+		plan.ManagedApplicationIdentity = planned.ManagedApplicationIdentity
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(setApplicationIdentity(ctx, resp.Identity, plan)...)
 }
 
 func (r *applicationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -383,8 +1135,18 @@ func (r *applicationResource) Read(ctx context.Context, req resource.ReadRequest
 		resp.Diagnostics.AddError("Failed to read application", err.Error())
 		return
 	}
+	if state.DeletionProtection.IsNull() {
+		state.DeletionProtection = types.BoolValue(false)
+	}
+	if state.ForceRemove.IsNull() {
+		state.ForceRemove = types.BoolValue(false)
+	}
+	if state.WaitForUpgrade.IsNull() {
+		state.WaitForUpgrade = types.BoolValue(true)
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+	resp.Diagnostics.Append(setApplicationIdentity(ctx, resp.Identity, state)...)
 }
 
 func (r *applicationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -440,50 +1202,48 @@ func (r *applicationResource) Update(ctx context.Context, req resource.UpdateReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	if !managedApplicationIdentityEqual(planIdentity, stateIdentity) {
+	if planIdentity == nil && stateIdentity != nil {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("managed_application_identity"),
-			"Managed application identity changes require recreation",
-			"Updating managed_application_identity for an existing Service Fabric application is not supported. Please recreate the resource to apply changes.",
+			"Removing managed application identities requires recreation",
+			"Service Fabric cannot remove all managed identities from an existing application. Please recreate the resource to apply changes.",
 		)
 		return
 	}
+	identityChanged := !managedApplicationIdentityEqual(planIdentity, stateIdentity)
 
-	planUpgradePolicy, policyDiags := expandApplicationUpgradePolicy(ctx, plan.UpgradePolicy)
+	planUpgradePolicy, policyDiags := expandApplicationUpgradePolicy(ctx, r.defaults.mergeUpgradePolicy(plan.UpgradePolicy))
 	resp.Diagnostics.Append(policyDiags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	stateParams := map[string]string{}
-	if !state.Parameters.IsNull() && !state.Parameters.IsUnknown() {
-		diag := state.Parameters.ElementsAs(ctx, &stateParams, false)
-		resp.Diagnostics.Append(diag...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
+	stateParams, stateParamDiags := deployedParameters(ctx, state)
+	resp.Diagnostics.Append(stateParamDiags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	planParams := map[string]string{}
-	if !plan.Parameters.IsNull() && !plan.Parameters.IsUnknown() {
-		diag := plan.Parameters.ElementsAs(ctx, &planParams, false)
+	planParams := stateParams
+	if !plan.EffectiveParameters.IsNull() && !plan.EffectiveParameters.IsUnknown() {
+		planParams = map[string]string{}
+		diag := plan.EffectiveParameters.ElementsAs(ctx, &planParams, false)
 		resp.Diagnostics.Append(diag...)
 		if resp.Diagnostics.HasError() {
 			return
 		}
-	} else {
-		planParams = stateParams
 	}
 
 	versionChanged := plan.TypeVersion.ValueString() != state.TypeVersion.ValueString()
 	parametersChanged := !stringMapEqual(planParams, stateParams)
 
-	if !versionChanged && !parametersChanged {
+	if !versionChanged && !parametersChanged && !identityChanged {
 		if err := r.refreshState(ctx, &plan); err != nil {
 			resp.Diagnostics.AddError("Failed to read application", err.Error())
 			return
 		}
 		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		resp.Diagnostics.Append(setApplicationIdentity(ctx, resp.Identity, plan)...)
 		return
 	}
 
@@ -491,8 +1251,9 @@ func (r *applicationResource) Update(ctx context.Context, req resource.UpdateReq
 		Name:                         plan.Name.ValueString(),
 		TargetApplicationTypeVersion: plan.TypeVersion.ValueString(),
 		ParameterMap:                 planParams,
+		ManagedApplicationIdentity:   planIdentity,
 	}
-	applyUpgradePolicy(&upgradeDesc, planUpgradePolicy, false)
+	applyUpgradePolicy(&upgradeDesc, planUpgradePolicy, plan.replacementStrategy() == replacementStrategyUpgradeForceRestart)
 
 	tflog.Info(ctx, "Starting Service Fabric application upgrade", map[string]any{
 		"name":              plan.Name.ValueString(),
@@ -500,21 +1261,80 @@ func (r *applicationResource) Update(ctx context.Context, req resource.UpdateReq
 		"type_name":         plan.TypeName.ValueString(),
 		"parametersChanged": parametersChanged,
 		"versionChanged":    versionChanged,
+		"identityChanged":   identityChanged,
 	})
 
-	if err := r.client.UpgradeApplication(ctx, upgradeDesc); err != nil {
+	unlock, err := r.coordinator.lockApplication(ctx, plan.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to upgrade application", err.Error())
+		return
+	}
+	defer unlock()
+
+	wait := true
+	if v, ok := boolValue(plan.WaitForUpgrade); ok {
+		wait = v
+	}
+	// An upgrade started by an earlier non-blocking apply may still be rolling
+	// out exactly what is planned; follow it rather than starting another. An
+	// upgrade to anything else has to finish before the planned one can start.
+	resume := false
+	if servicefabric.IsUpgradeInProgress(state.UpgradeState.ValueString()) {
+		progress, progressErr := r.client.GetApplicationUpgradeProgress(ctx, plan.Name.ValueString())
+		if progressErr != nil {
+			resp.Diagnostics.AddError("Failed to read application upgrade progress", progressErr.Error())
+			return
+		}
+		inProgress := servicefabric.IsUpgradeInProgress(progress.UpgradeState)
+		resume = inProgress && upgradeMatches(progress, upgradeDesc)
+		if inProgress && !resume {
+			tflog.Info(ctx, "Waiting for the running Service Fabric application upgrade before starting the planned one", map[string]any{
+				"name":                plan.Name.ValueString(),
+				"target_type_version": progress.TargetApplicationTypeVersion,
+			})
+			if waitErr := r.client.WaitForApplicationUpgradeIdle(ctx, plan.Name.ValueString()); waitErr != nil {
+				resp.Diagnostics.AddError("Failed to upgrade application", waitErr.Error())
+				return
+			}
+		}
+	}
+	switch {
+	case resume && wait:
+		err = r.client.WaitForApplicationUpgrade(ctx, plan.Name.ValueString())
+	case resume:
+		tflog.Info(ctx, "Service Fabric application upgrade already in progress", map[string]any{
+			"name":         plan.Name.ValueString(),
+			"type_version": plan.TypeVersion.ValueString(),
+		})
+	case wait:
+		err = r.client.UpgradeApplication(ctx, upgradeDesc)
+	default:
+		err = r.client.StartApplicationUpgrade(ctx, upgradeDesc)
+	}
+	if err != nil {
 		resp.Diagnostics.AddError("Failed to upgrade application", err.Error())
 		return
 	}
 
+	planned := plan
 	if err := r.refreshState(ctx, &plan); err != nil {
 		resp.Diagnostics.AddError("Failed to read application", err.Error())
 		return
 	}
+	if !wait {
+		// The cluster keeps reporting the previous deployment until the upgrade
+		// completes. Record what was requested; the next refresh shows whatever
+		// has not rolled out yet as drift.
+		plan.TypeVersion = planned.TypeVersion
+		plan.Parameters = planned.Parameters
+		plan.EffectiveParameters = types.MapValueMust(types.StringType, convertStringMapToAttrValues(planParams))
+		plan.ManagedApplicationIdentity = planned.ManagedApplicationIdentity
+	}
 
 	plan.ID = types.StringValue(applicationCompositeID(plan.TypeName.ValueString(), plan.Name.ValueString()))
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(setApplicationIdentity(ctx, resp.Identity, plan)...)
 }
 
 func (r *applicationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -524,7 +1344,20 @@ func (r *applicationResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
-	if err := r.client.DeleteApplication(ctx, state.Name.ValueString(), false); err != nil {
+	checkDeletionProtection(ctx, req.State, &resp.Diagnostics, "application")
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	unlock, err := r.coordinator.lockApplication(ctx, state.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to delete application", err.Error())
+		return
+	}
+	defer unlock()
+
+	forceDelete, _ := boolValue(state.ForceRemove)
+	if err := r.client.DeleteApplication(ctx, state.Name.ValueString(), forceDelete); err != nil {
 		if servicefabric.IsNotFoundError(err) {
 			return
 		}
@@ -534,6 +1367,14 @@ func (r *applicationResource) Delete(ctx context.Context, req resource.DeleteReq
 
 func (r *applicationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	id := req.ID
+	if id == "" && req.Identity != nil {
+		var identity applicationIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		id = identity.Name.ValueString()
+	}
 	if id == "" {
 		resp.Diagnostics.AddError("Missing identifier", "Import requires an application name.")
 		return
@@ -819,6 +1660,18 @@ func applicationCapacityEqual(a, b *servicefabric.ApplicationCapacityDescription
 	return true
 }
 
+// upgradeMatches reports whether the upgrade described by progress was started
+// with the type version, parameters and managed identities of desc.
+func upgradeMatches(progress *servicefabric.ApplicationUpgradeProgress, desc servicefabric.ApplicationUpgradeDescription) bool {
+	started := progress.UpgradeDescription
+	if started == nil {
+		return false
+	}
+	return started.TargetApplicationTypeVersion == desc.TargetApplicationTypeVersion &&
+		stringMapEqual(servicefabric.ParameterListToMap(started.Parameters), desc.ParameterMap) &&
+		managedApplicationIdentityEqual(started.ManagedApplicationIdentity, desc.ManagedApplicationIdentity)
+}
+
 func managedApplicationIdentityEqual(a, b *servicefabric.ManagedApplicationIdentityDescription) bool {
 	if a == nil && b == nil {
 		return true
@@ -868,6 +1721,22 @@ func expandApplicationUpgradePolicy(ctx context.Context, model *upgradePolicyMod
 		result.UpgradeMode = v
 		hasValue = true
 	}
+	if v, ok := int64Value(model.UpgradeReplicaSetCheckTimeout); ok {
+		result.UpgradeReplicaSetCheckTimeout = &v
+		hasValue = true
+	}
+	if v, ok := stringValue(model.SortOrder); ok {
+		result.SortOrder = v
+		hasValue = true
+	}
+	if v, ok := int64Value(model.InstanceCloseDelayDuration); ok {
+		result.InstanceCloseDelayDuration = &v
+		hasValue = true
+	}
+	if v, ok := boolValue(model.RecreateApplication); ok && v {
+		result.RecreateApplication = true
+		hasValue = true
+	}
 
 	monitoring, monitoringDiags := expandMonitoringPolicy(ctx, model.MonitoringPolicy)
 	diags.Append(monitoringDiags...)
@@ -950,17 +1819,58 @@ func expandApplicationHealthPolicy(_ context.Context, model *applicationHealthPo
 		policy.MaxPercentUnhealthyDeployedApplications = &v
 		hasValue = true
 	}
+	if model.DefaultServiceTypeHealthPolicy != nil {
+		defaultPolicy := expandServiceTypeHealthPolicy(*model.DefaultServiceTypeHealthPolicy)
+		if defaultPolicy != (servicefabric.ServiceTypeHealthPolicy{}) {
+			policy.DefaultServiceTypeHealthPolicy = &defaultPolicy
+			hasValue = true
+		}
+	}
+	for _, entry := range model.ServiceTypeHealthPolicies {
+		name, ok := stringValue(entry.ServiceTypeName)
+		if !ok || name == "" {
+			continue
+		}
+		policy.ServiceTypeHealthPolicyMap = append(policy.ServiceTypeHealthPolicyMap, servicefabric.ServiceTypeHealthPolicyMapItem{
+			Key: name,
+			Value: expandServiceTypeHealthPolicy(serviceTypeHealthPolicyModel{
+				MaxPercentUnhealthyServices:             entry.MaxPercentUnhealthyServices,
+				MaxPercentUnhealthyPartitionsPerService: entry.MaxPercentUnhealthyPartitionsPerService,
+				MaxPercentUnhealthyReplicasPerPartition: entry.MaxPercentUnhealthyReplicasPerPartition,
+			}),
+		})
+		hasValue = true
+	}
 	if !hasValue {
 		return nil, diags
 	}
 	return policy, diags
 }
 
+func expandServiceTypeHealthPolicy(model serviceTypeHealthPolicyModel) servicefabric.ServiceTypeHealthPolicy {
+	var policy servicefabric.ServiceTypeHealthPolicy
+	if v, ok := int64Value(model.MaxPercentUnhealthyServices); ok {
+		policy.MaxPercentUnhealthyServices = &v
+	}
+	if v, ok := int64Value(model.MaxPercentUnhealthyPartitionsPerService); ok {
+		policy.MaxPercentUnhealthyPartitionsPerService = &v
+	}
+	if v, ok := int64Value(model.MaxPercentUnhealthyReplicasPerPartition); ok {
+		policy.MaxPercentUnhealthyReplicasPerPartition = &v
+	}
+	return policy
+}
+
 func applyUpgradePolicy(desc *servicefabric.ApplicationUpgradeDescription, policy *applicationUpgradePolicy, defaultForce bool) {
 	if policy == nil {
 		desc.ForceRestart = defaultForce
 		return
 	}
+	if policy.RecreateApplication {
+		// Service Fabric rejects any other upgrade setting alongside RecreateApplication.
+		desc.RecreateApplication = true
+		return
+	}
 	if policy.ForceRestart != nil {
 		desc.ForceRestart = *policy.ForceRestart
 	} else {
@@ -969,6 +1879,9 @@ func applyUpgradePolicy(desc *servicefabric.ApplicationUpgradeDescription, polic
 	if policy.UpgradeMode != "" {
 		desc.RollingUpgradeMode = policy.UpgradeMode
 	}
+	desc.UpgradeReplicaSetCheckTimeoutInSeconds = policy.UpgradeReplicaSetCheckTimeout
+	desc.SortOrder = policy.SortOrder
+	desc.InstanceCloseDelayDurationInSeconds = policy.InstanceCloseDelayDuration
 	if policy.MonitoringPolicy != nil {
 		desc.MonitoringPolicy = policy.MonitoringPolicy
 	}
@@ -1010,11 +1923,67 @@ func isGUID(v string) bool {
 }
 
 func (r *applicationResource) refreshState(ctx context.Context, state *applicationResourceModel) error {
+	prior := state.Parameters
 	info, err := r.client.GetApplication(ctx, state.Name.ValueString())
 	if err != nil {
 		return err
 	}
+	if err := applyApplicationInfo(ctx, state, info); err != nil {
+		return err
+	}
+	state.Parameters = r.ownParameters(ctx, prior, servicefabric.ParameterListToMap(info.ParameterEntries()))
+
+	progress, err := r.client.GetApplicationUpgradeProgress(ctx, state.Name.ValueString())
+	if err != nil && !servicefabric.IsNotFoundError(err) {
+		return err
+	}
+	applyUpgradeProgress(state, progress)
+	return nil
+}
+
+// applyUpgradeProgress records the most recent upgrade; a nil progress or one
+// without state clears the upgrade attributes.
+func applyUpgradeProgress(state *applicationResourceModel, progress *servicefabric.ApplicationUpgradeProgress) {
+	state.UpgradeState = types.StringNull()
+	state.TargetTypeVersion = types.StringNull()
+	state.UpgradeDomainProgress = types.MapNull(types.StringType)
+	if progress == nil || progress.UpgradeState == "" {
+		return
+	}
+	state.UpgradeState = types.StringValue(progress.UpgradeState)
+	if progress.TargetApplicationTypeVersion != "" {
+		state.TargetTypeVersion = types.StringValue(progress.TargetApplicationTypeVersion)
+	}
+	domains := make(map[string]string, len(progress.UpgradeDomains))
+	for _, domain := range progress.UpgradeDomains {
+		domains[domain.Name] = domain.State
+	}
+	state.UpgradeDomainProgress = types.MapValueMust(types.StringType, convertStringMapToAttrValues(domains))
+}
 
+// ownParameters returns the deployed parameters that belong in parameters:
+// values inherited unchanged from default_application_parameters are left out
+// unless the prior value sets them, so defaults do not show up as drift.
+func (r *applicationResource) ownParameters(ctx context.Context, prior types.Map, deployed map[string]string) types.Map {
+	configured := map[string]string{}
+	if !prior.IsNull() && !prior.IsUnknown() {
+		_ = prior.ElementsAs(ctx, &configured, false)
+	}
+	own := make(map[string]string, len(deployed))
+	for name, value := range deployed {
+		_, isConfigured := configured[name]
+		if defaultValue, ok := r.defaults.Parameters[name]; ok && defaultValue == value && !isConfigured {
+			continue
+		}
+		own[name] = value
+	}
+	if len(own) == 0 && prior.IsNull() {
+		return types.MapNull(types.StringType)
+	}
+	return types.MapValueMust(types.StringType, convertStringMapToAttrValues(own))
+}
+
+func applyApplicationInfo(ctx context.Context, state *applicationResourceModel, info *servicefabric.ApplicationInfo) error {
 	state.Name = types.StringValue(info.Name)
 	state.TypeName = types.StringValue(info.TypeName)
 	state.TypeVersion = types.StringValue(info.TypeVersion)
@@ -1024,6 +1993,7 @@ func (r *applicationResource) refreshState(ctx context.Context, state *applicati
 
 	params := servicefabric.ParameterListToMap(info.ParameterEntries())
 	state.Parameters = types.MapValueMust(types.StringType, convertStringMapToAttrValues(params))
+	state.EffectiveParameters = state.Parameters
 
 	state.ApplicationCapacity = types.ObjectNull(applicationCapacityAttrTypes)
 	if info.ApplicationCapacity != nil {
@@ -1045,3 +2015,12 @@ func (r *applicationResource) refreshState(ctx context.Context, state *applicati
 
 	return nil
 }
+
+func setApplicationIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, state applicationResourceModel) diag.Diagnostics {
+	if identity == nil {
+		return nil
+	}
+	return identity.Set(ctx, applicationIdentityModel{
+		Name: state.Name,
+	})
+}
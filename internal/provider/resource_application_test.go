@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testApplicationModel(parameters, effective map[string]string) applicationResourceModel {
+	model := applicationResourceModel{
+		ID:                         types.StringValue("App~fabric:/App"),
+		Name:                       types.StringValue("fabric:/App"),
+		TypeName:                   types.StringValue("App"),
+		TypeVersion:                types.StringValue("1.0.0"),
+		Parameters:                 types.MapNull(types.StringType),
+		EffectiveParameters:        types.MapNull(types.StringType),
+		ApplicationCapacity:        types.ObjectNull(applicationCapacityAttrTypes),
+		ManagedApplicationIdentity: types.ObjectNull(managedApplicationIdentityAttrTypes),
+		UpgradeDomainProgress:      types.MapNull(types.StringType),
+	}
+	if parameters != nil {
+		model.Parameters = types.MapValueMust(types.StringType, convertStringMapToAttrValues(parameters))
+	}
+	if effective != nil {
+		model.EffectiveParameters = types.MapValueMust(types.StringType, convertStringMapToAttrValues(effective))
+	}
+	return model
+}
+
+func TestApplicationModifyPlanRemovedParameters(t *testing.T) {
+	ctx := context.Background()
+	r := &applicationResource{defaults: applicationDefaults{Parameters: map[string]string{"Region": "west"}}}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	schema := schemaResp.Schema
+
+	state := tfsdk.State{Schema: schema}
+	if diags := state.Set(ctx, testApplicationModel(
+		map[string]string{"InstanceCount": "3"},
+		map[string]string{"InstanceCount": "3", "Region": "west"},
+	)); diags.HasError() {
+		t.Fatalf("setting state: %v", diags)
+	}
+	plan := tfsdk.Plan{Schema: schema}
+	if diags := plan.Set(ctx, testApplicationModel(nil, nil)); diags.HasError() {
+		t.Fatalf("setting plan: %v", diags)
+	}
+
+	req := resource.ModifyPlanRequest{State: state, Plan: plan, Config: tfsdk.Config{Schema: schema, Raw: plan.Raw}}
+	resp := resource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("ModifyPlan() returned errors: %v", resp.Diagnostics)
+	}
+
+	var got applicationResourceModel
+	if diags := resp.Plan.Get(ctx, &got); diags.HasError() {
+		t.Fatalf("reading plan: %v", diags)
+	}
+	if !got.Parameters.IsNull() {
+		t.Errorf("parameters = %s, want null", got.Parameters)
+	}
+	effective := map[string]string{}
+	if diags := got.EffectiveParameters.ElementsAs(ctx, &effective, false); diags.HasError() {
+		t.Fatalf("reading effective_parameters: %v", diags)
+	}
+	if want := map[string]string{"Region": "west"}; !stringMapEqual(effective, want) {
+		t.Errorf("effective_parameters = %v, want %v", effective, want)
+	}
+
+	// Once the upgrade has rolled out, the refreshed parameters stay null.
+	if own := r.ownParameters(ctx, got.Parameters, effective); !own.IsNull() {
+		t.Errorf("ownParameters() = %s, want null", own)
+	}
+}
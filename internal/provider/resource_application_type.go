@@ -3,34 +3,38 @@ package provider
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	stringplanmodifier "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/williamoconnorme/terraform-provider-servicefabric/internal/servicefabric"
 )
 
 var _ resource.Resource = &applicationTypeResource{}
 var _ resource.ResourceWithImportState = &applicationTypeResource{}
+var _ resource.ResourceWithIdentity = &applicationTypeResource{}
+var _ resource.ResourceWithUpgradeState = &applicationTypeResource{}
+var _ resource.ResourceWithModifyPlan = &applicationTypeResource{}
 
 type applicationTypeResource struct {
-	client   *servicefabric.Client
-	features providerFeatures
-}
-
-func (r *applicationTypeResource) computeID(name, version string) string {
-	if allowApplicationTypeUpdatesEnabled() || r.features.AllowApplicationTypeUpdates {
-		return name
-	}
-	return fmt.Sprintf("%s/%s", name, version)
+	client      *servicefabric.Client
+	features    providerFeatures
+	coordinator *operationCoordinator
 }
 
 type featureAwareVersionPlanModifier struct {
@@ -52,13 +56,60 @@ func (m featureAwareVersionPlanModifier) PlanModifyString(ctx context.Context, r
 	stringplanmodifier.RequiresReplace().PlanModifyString(ctx, req, resp)
 }
 
+// applicationTypeIDPlanModifier plans the identifier from name and version so
+// in-place version updates report the new identifier up front.
+type applicationTypeIDPlanModifier struct{}
+
+func (m applicationTypeIDPlanModifier) Description(_ context.Context) string {
+	return "Derives the identifier from the planned name and version."
+}
+
+func (m applicationTypeIDPlanModifier) MarkdownDescription(_ context.Context) string {
+	return "Derives the identifier from the planned `name` and `version`."
+}
+
+func (m applicationTypeIDPlanModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	var name, version types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("version"), &version)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	n, okName := stringValue(name)
+	v, okVersion := stringValue(version)
+	if !okName || !okVersion {
+		return
+	}
+	resp.PlanValue = types.StringValue(applicationTypeID(n, v))
+}
+
 type applicationTypeResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	Name           types.String `tfsdk:"name"`
-	Version        types.String `tfsdk:"version"`
-	PackageURI     types.String `tfsdk:"package_uri"`
-	Status         types.String `tfsdk:"status"`
-	RetainVersions types.Bool   `tfsdk:"retain_versions"`
+	ID                 types.String `tfsdk:"id"`
+	Name               types.String `tfsdk:"name"`
+	Version            types.String `tfsdk:"version"`
+	PackageURI         types.String `tfsdk:"package_uri"`
+	Status             types.String `tfsdk:"status"`
+	RetainVersions     types.Bool   `tfsdk:"retain_versions"`
+	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
+	VersionRetention   types.Object `tfsdk:"version_retention"`
+	// ProvisionedVersions lists the versions this resource has registered and
+	// not yet unprovisioned; version retention only ever removes these.
+	ProvisionedVersions types.List `tfsdk:"provisioned_versions"`
+}
+
+type versionRetentionModel struct {
+	KeepLast                 types.Int64 `tfsdk:"keep_last"`
+	IncludeUnmanagedVersions types.Bool  `tfsdk:"include_unmanaged_versions"`
+}
+
+var versionRetentionAttrTypes = map[string]attr.Type{
+	"keep_last":                  types.Int64Type,
+	"include_unmanaged_versions": types.BoolType,
+}
+
+type applicationTypeIdentityModel struct {
+	Name    types.String `tfsdk:"name"`
+	Version types.String `tfsdk:"version"`
 }
 
 func NewApplicationTypeResource() resource.Resource {
@@ -67,15 +118,34 @@ func NewApplicationTypeResource() resource.Resource {
 
 func (r *applicationTypeResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_application_type"
+	// The version is part of the identity and may change in place when
+	// allow_application_type_version_updates is enabled.
+	resp.ResourceBehavior.MutableIdentity = true
+}
+
+func (r *applicationTypeResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"name": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Application type name as registered in the cluster.",
+			},
+			"version": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Application type version.",
+			},
+		},
+	}
 }
 
 func (r *applicationTypeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = rschema.Schema{
+		Version: 1,
 		Attributes: map[string]rschema.Attribute{
 			"id": rschema.StringAttribute{
 				Computed:      true,
 				Description:   "Unique identifier in the format \"{name}/{version}\".",
-				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+				PlanModifiers: []planmodifier.String{applicationTypeIDPlanModifier{}},
 			},
 			"name": rschema.StringAttribute{
 				Required:    true,
@@ -108,6 +178,64 @@ func (r *applicationTypeResource) Schema(_ context.Context, _ resource.SchemaReq
 				Default:     booldefault.StaticBool(false),
 				Description: "When true, previously provisioned versions are retained in the cluster instead of being unprovisioned on destroy.",
 			},
+			"deletion_protection": rschema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "When true, destroying or replacing the application type version fails. Set to false and apply before destroying it.",
+			},
+			"version_retention": rschema.SingleNestedAttribute{
+				Optional: true,
+				Description: "Garbage collection policy for older versions. Applied after each successful provision and when the policy changes. " +
+					"Only versions registered by this resource are considered unless include_unmanaged_versions is set; versions skipped because an application still uses them are retried on the next run.",
+				Attributes: map[string]rschema.Attribute{
+					"keep_last": rschema.Int64Attribute{
+						Required:    true,
+						Description: "Number of most recent versions, sorted semantically, to keep provisioned. Older ones not referenced by any application are unprovisioned.",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"include_unmanaged_versions": rschema.BoolAttribute{
+						Optional: true,
+						Description: "When true, every version of the application type registered in the cluster is a candidate, including those provisioned by replaced instances of this resource, " +
+							"other resources or outside Terraform. Required for retention to collect versions left behind by replacements with retain_versions = true.",
+					},
+				},
+			},
+			"provisioned_versions": rschema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Versions of the application type this resource has registered and not yet unprovisioned. Version retention only removes versions from this list.",
+			},
+		},
+	}
+}
+
+// UpgradeState migrates version 0 states, whose identifier was "{name}" while
+// allow_application_type_version_updates was enabled, to the "{name}/{version}" format.
+func (r *applicationTypeResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			StateUpgrader: func(_ context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				upgradeRawState(req, resp, func(attrs map[string]any) {
+					id, _ := attrs["id"].(string)
+					idName, idVersion := splitApplicationTypeID(id)
+					name, _ := attrs["name"].(string)
+					version, _ := attrs["version"].(string)
+					if name == "" {
+						name = idName
+						attrs["name"] = name
+					}
+					if version == "" && idVersion != "" {
+						version = idVersion
+						attrs["version"] = version
+					}
+					if name != "" && version != "" {
+						attrs["id"] = applicationTypeID(name, version)
+					}
+				})
+			},
 		},
 	}
 }
@@ -122,6 +250,7 @@ func (r *applicationTypeResource) Configure(_ context.Context, req resource.Conf
 	}
 	r.client = data.Client
 	r.features = data.Features
+	r.coordinator = data.Coordinator
 }
 
 func (r *applicationTypeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -135,6 +264,13 @@ func (r *applicationTypeResource) Create(ctx context.Context, req resource.Creat
 		plan.RetainVersions = types.BoolValue(false)
 	}
 
+	unlock, err := r.coordinator.lockApplicationType(ctx, plan.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Provisioning failed", err.Error())
+		return
+	}
+	defer unlock()
+
 	if err := r.client.ProvisionApplicationType(ctx, plan.Name.ValueString(), plan.Version.ValueString(), plan.PackageURI.ValueString()); err != nil {
 		if servicefabric.IsApplicationTypeAlreadyExistsError(err) {
 			tflog.Info(ctx, "Application type version already provisioned", map[string]any{
@@ -152,14 +288,16 @@ func (r *applicationTypeResource) Create(ctx context.Context, req resource.Creat
 		"version": plan.Version.ValueString(),
 	})
 
-	plan.ID = types.StringValue(r.computeID(plan.Name.ValueString(), plan.Version.ValueString()))
+	plan.ID = types.StringValue(applicationTypeID(plan.Name.ValueString(), plan.Version.ValueString()))
 
 	if err := r.readIntoState(ctx, &plan); err != nil {
 		resp.Diagnostics.AddError("Failed to read application type", err.Error())
 		return
 	}
 
+	resp.Diagnostics.Append(r.applyVersionRetention(ctx, &plan)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(setApplicationTypeIdentity(ctx, resp.Identity, plan)...)
 }
 
 func (r *applicationTypeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -179,6 +317,7 @@ func (r *applicationTypeResource) Read(ctx context.Context, req resource.ReadReq
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+	resp.Diagnostics.Append(setApplicationTypeIdentity(ctx, resp.Identity, state)...)
 }
 
 func (r *applicationTypeResource) readIntoState(ctx context.Context, state *applicationTypeResourceModel) error {
@@ -186,12 +325,155 @@ func (r *applicationTypeResource) readIntoState(ctx context.Context, state *appl
 	if err != nil {
 		return err
 	}
+	r.applyInfoToState(state, info)
+	return nil
+}
+
+func (r *applicationTypeResource) applyInfoToState(state *applicationTypeResourceModel, info *servicefabric.ApplicationTypeInfo) {
 	state.Status = types.StringValue(info.Status)
-	state.ID = types.StringValue(r.computeID(state.Name.ValueString(), state.Version.ValueString()))
+	state.ID = types.StringValue(applicationTypeID(state.Name.ValueString(), state.Version.ValueString()))
 	if state.RetainVersions.IsNull() || state.RetainVersions.IsUnknown() {
 		state.RetainVersions = types.BoolValue(false)
 	}
-	return nil
+	if state.DeletionProtection.IsNull() || state.DeletionProtection.IsUnknown() {
+		state.DeletionProtection = types.BoolValue(false)
+	}
+	// States written before versions were tracked only know the current one.
+	if state.ProvisionedVersions.IsNull() || state.ProvisionedVersions.IsUnknown() {
+		state.ProvisionedVersions = types.ListValueMust(types.StringType, []attr.Value{state.Version})
+	}
+}
+
+// applyVersionRetention unprovisions versions that fall outside the configured
+// retention window and drops them from ProvisionedVersions. Candidates are the
+// versions registered by this resource and, with include_unmanaged_versions,
+// every version of the type in the cluster. Versions still referenced by an
+// application are skipped with a warning; failures never fail the apply since
+// the managed version itself was provisioned successfully.
+func (r *applicationTypeResource) applyVersionRetention(ctx context.Context, state *applicationTypeResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if state.VersionRetention.IsNull() || state.VersionRetention.IsUnknown() {
+		return diags
+	}
+	var retention versionRetentionModel
+	diags.Append(state.VersionRetention.As(ctx, &retention, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return diags
+	}
+	keepLast, ok := int64Value(retention.KeepLast)
+	if !ok || keepLast < 1 {
+		return diags
+	}
+
+	var provisioned []string
+	diags.Append(state.ProvisionedVersions.ElementsAs(ctx, &provisioned, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	name := state.Name.ValueString()
+	current := state.Version.ValueString()
+
+	versions := append([]string(nil), provisioned...)
+	if includeUnmanaged, _ := boolValue(retention.IncludeUnmanagedVersions); includeUnmanaged {
+		registered, err := r.client.ListApplicationTypeVersions(ctx, name)
+		if err != nil {
+			diags.AddWarning("Version retention skipped", fmt.Sprintf("Failed to list versions of application type %s: %s", name, err))
+			return diags
+		}
+		for _, info := range registered {
+			if !containsFold(versions, info.Version) {
+				versions = append(versions, info.Version)
+			}
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return compareApplicationTypeVersions(versions[i], versions[j]) > 0
+	})
+	if int64(len(versions)) <= keepLast {
+		return diags
+	}
+
+	apps, err := r.client.ListApplications(ctx, name)
+	if err != nil {
+		diags.AddWarning("Version retention skipped", fmt.Sprintf("Failed to list applications of type %s: %s", name, err))
+		return diags
+	}
+	inUse := make(map[string][]string)
+	for _, app := range apps {
+		version := strings.ToLower(app.TypeVersion)
+		inUse[version] = append(inUse[version], app.Name)
+	}
+
+	var removed []string
+	for _, version := range versions[keepLast:] {
+		if strings.EqualFold(version, current) {
+			continue
+		}
+		if users := inUse[strings.ToLower(version)]; len(users) > 0 {
+			diags.AddWarning(
+				"Application type version still in use",
+				fmt.Sprintf("Version retention skipped %s/%s because it is referenced by: %s. It is retried on the next provision.", name, version, strings.Join(users, ", ")),
+			)
+			continue
+		}
+
+		err := r.client.UnprovisionApplicationType(ctx, name, version, false)
+		switch {
+		case err == nil:
+			tflog.Info(ctx, "Unprovisioned Service Fabric application type version per retention policy", map[string]any{
+				"name":    name,
+				"version": version,
+			})
+			removed = append(removed, version)
+		case servicefabric.IsNotFoundError(err):
+			removed = append(removed, version)
+		case servicefabric.IsApplicationTypeInUseError(err):
+			diags.AddWarning(
+				"Application type version still in use",
+				fmt.Sprintf("Version retention skipped %s/%s because it is still referenced by an application. It is retried on the next provision.", name, version),
+			)
+		default:
+			diags.AddWarning(
+				"Failed to unprovision application type version",
+				fmt.Sprintf("Version retention could not unprovision %s/%s: %s", name, version, err),
+			)
+		}
+	}
+
+	kept := make([]string, 0, len(provisioned))
+	for _, version := range provisioned {
+		if !containsFold(removed, version) {
+			kept = append(kept, version)
+		}
+	}
+
+	list, listDiags := types.ListValueFrom(ctx, types.StringType, kept)
+	diags.Append(listDiags...)
+	if !listDiags.HasError() {
+		state.ProvisionedVersions = list
+	}
+	return diags
+}
+
+// recordProvisionedVersion adds version to the versions registered by the
+// resource, carrying over those recorded in prior.
+func recordProvisionedVersion(ctx context.Context, prior types.List, version string) (types.List, diag.Diagnostics) {
+	var versions []string
+	diags := prior.ElementsAs(ctx, &versions, false)
+	if containsFold(versions, version) {
+		return prior, diags
+	}
+	return types.ListValueFrom(ctx, types.StringType, append(versions, version))
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *applicationTypeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -212,6 +494,10 @@ func (r *applicationTypeResource) Update(ctx context.Context, req resource.Updat
 	if plan.ID.IsNull() || plan.ID.IsUnknown() {
 		plan.ID = state.ID
 	}
+	plan.ProvisionedVersions = state.ProvisionedVersions
+	if plan.ProvisionedVersions.IsNull() {
+		plan.ProvisionedVersions = types.ListValueMust(types.StringType, []attr.Value{state.Version})
+	}
 
 	versionChanged := plan.Version.ValueString() != state.Version.ValueString()
 	packageChanged := plan.PackageURI.ValueString() != state.PackageURI.ValueString()
@@ -224,8 +510,19 @@ func (r *applicationTypeResource) Update(ctx context.Context, req resource.Updat
 		return
 	}
 
+	unlock, err := r.coordinator.lockApplicationType(ctx, plan.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Provisioning failed", err.Error())
+		return
+	}
+	defer unlock()
+
 	if !versionChanged && !packageChanged {
+		if !plan.VersionRetention.Equal(state.VersionRetention) {
+			resp.Diagnostics.Append(r.applyVersionRetention(ctx, &plan)...)
+		}
 		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		resp.Diagnostics.Append(setApplicationTypeIdentity(ctx, resp.Identity, plan)...)
 		return
 	}
 
@@ -241,14 +538,30 @@ func (r *applicationTypeResource) Update(ctx context.Context, req resource.Updat
 		}
 	}
 
-	plan.ID = types.StringValue(r.computeID(plan.Name.ValueString(), plan.Version.ValueString()))
+	plan.ID = types.StringValue(applicationTypeID(plan.Name.ValueString(), plan.Version.ValueString()))
+
+	versions, diags := recordProvisionedVersion(ctx, plan.ProvisionedVersions, plan.Version.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.ProvisionedVersions = versions
 
 	if err := r.readIntoState(ctx, &plan); err != nil {
 		resp.Diagnostics.AddError("Failed to read application type", err.Error())
 		return
 	}
 
+	resp.Diagnostics.Append(r.applyVersionRetention(ctx, &plan)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(setApplicationTypeIdentity(ctx, resp.Identity, plan)...)
+}
+
+// ModifyPlan rejects destroy plans for protected application type versions.
+func (r *applicationTypeResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		checkDeletionProtection(ctx, req.State, &resp.Diagnostics, "application type")
+	}
 }
 
 func (r *applicationTypeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -258,6 +571,11 @@ func (r *applicationTypeResource) Delete(ctx context.Context, req resource.Delet
 		return
 	}
 
+	checkDeletionProtection(ctx, req.State, &resp.Diagnostics, "application type")
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	retain := true
 	if !state.RetainVersions.IsNull() && !state.RetainVersions.IsUnknown() {
 		retain = state.RetainVersions.ValueBool()
@@ -270,30 +588,101 @@ func (r *applicationTypeResource) Delete(ctx context.Context, req resource.Delet
 		return
 	}
 
-	err := r.client.UnprovisionApplicationType(ctx, state.Name.ValueString(), state.Version.ValueString(), false)
+	// Versions version retention kept, for example because an application
+	// still used them, belong to this resource too.
+	var versions []string
+	if !state.ProvisionedVersions.IsNull() && !state.ProvisionedVersions.IsUnknown() {
+		resp.Diagnostics.Append(state.ProvisionedVersions.ElementsAs(ctx, &versions, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if !containsFold(versions, state.Version.ValueString()) {
+		versions = append(versions, state.Version.ValueString())
+	}
+
+	unlock, err := r.coordinator.lockApplicationType(ctx, state.Name.ValueString())
 	if err != nil {
+		resp.Diagnostics.AddError("Failed to unprovision application type", err.Error())
+		return
+	}
+	defer unlock()
+
+	name := state.Name.ValueString()
+	for _, version := range versions {
+		err := r.client.UnprovisionApplicationType(ctx, name, version, false)
 		switch {
-		case servicefabric.IsNotFoundError(err):
-			return
+		case err == nil, servicefabric.IsNotFoundError(err):
 		case servicefabric.IsApplicationTypeInUseError(err):
 			resp.Diagnostics.AddWarning(
 				"Application type still in use",
-				fmt.Sprintf("Skipped unprovisioning %s/%s because it is still referenced by an application. Service Fabric will retain older versions until no longer needed.", state.Name.ValueString(), state.Version.ValueString()),
+				fmt.Sprintf("Skipped unprovisioning %s/%s because it is still referenced by an application. Service Fabric will retain older versions until no longer needed.", name, version),
 			)
 		default:
-			resp.Diagnostics.AddError("Failed to unprovision application type", err.Error())
+			resp.Diagnostics.AddError("Failed to unprovision application type", fmt.Sprintf("%s/%s: %s", name, version, err))
 		}
 	}
 }
 
 func (r *applicationTypeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id := req.ID
-	parts := strings.SplitN(id, "/", 2)
-	if len(parts) != 2 {
+	var name, version string
+	if req.ID != "" {
+		name, version = splitApplicationTypeID(req.ID)
+	} else if req.Identity != nil {
+		var identity applicationTypeIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		name = identity.Name.ValueString()
+		version = identity.Version.ValueString()
+	}
+	if name == "" {
 		resp.Diagnostics.AddError("Unexpected import identifier", "Expected identifier in the format name/version.")
 		return
 	}
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), parts[0])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("version"), parts[1])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+
+	// The name-only format was used as ID while allow_application_type_version_updates
+	// was enabled; resolve the version when it is unambiguous.
+	if version == "" {
+		resolved, err := r.resolveSingleVersion(ctx, name)
+		if err != nil {
+			resp.Diagnostics.AddError("Unexpected import identifier", err.Error())
+			return
+		}
+		version = resolved
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("version"), version)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), applicationTypeID(name, version))...)
+}
+
+func (r *applicationTypeResource) resolveSingleVersion(ctx context.Context, name string) (string, error) {
+	infos, err := r.client.ListApplicationTypeVersions(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	switch len(infos) {
+	case 0:
+		return "", fmt.Errorf("application type %s is not provisioned in the cluster", name)
+	case 1:
+		return infos[0].TypeVersion(), nil
+	default:
+		versions := make([]string, 0, len(infos))
+		for _, info := range infos {
+			versions = append(versions, info.TypeVersion())
+		}
+		return "", fmt.Errorf("application type %s has multiple versions (%s); import with name/version", name, strings.Join(versions, ", "))
+	}
+}
+
+func setApplicationTypeIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, state applicationTypeResourceModel) diag.Diagnostics {
+	if identity == nil {
+		return nil
+	}
+	return identity.Set(ctx, applicationTypeIdentityModel{
+		Name:    state.Name,
+		Version: state.Version,
+	})
 }
@@ -7,23 +7,58 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	stringplanmodifier "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/williamoconnorme/terraform-provider-servicefabric/internal/servicefabric"
 )
 
 var _ resource.Resource = &serviceResource{}
+var _ resource.ResourceWithIdentity = &serviceResource{}
+var _ resource.ResourceWithImportState = &serviceResource{}
+var _ resource.ResourceWithModifyPlan = &serviceResource{}
+var _ resource.ResourceWithValidateConfig = &serviceResource{}
+
+var (
+	partitionAttrTypes = map[string]attr.Type{
+		"scheme":   types.StringType,
+		"count":    types.Int64Type,
+		"names":    types.ListType{ElemType: types.StringType},
+		"low_key":  types.Int64Type,
+		"high_key": types.Int64Type,
+	}
+	statelessServiceAttrTypes = map[string]attr.Type{
+		"instance_count":                types.Int64Type,
+		"min_instance_count":            types.Int64Type,
+		"min_instance_percentage":       types.Int64Type,
+		"instance_close_delay_seconds":  types.Int64Type,
+		"instance_restart_wait_seconds": types.Int64Type,
+	}
+	statefulServiceAttrTypes = map[string]attr.Type{
+		"target_replica_set_size":              types.Int64Type,
+		"min_replica_set_size":                 types.Int64Type,
+		"has_persisted_state":                  types.BoolType,
+		"replica_restart_wait_seconds":         types.Int64Type,
+		"quorum_loss_wait_seconds":             types.Int64Type,
+		"standby_replica_keep_seconds":         types.Int64Type,
+		"service_placement_time_limit_seconds": types.Int64Type,
+	}
+)
 
 type serviceResource struct {
-	client *servicefabric.Client
+	client      *servicefabric.Client
+	coordinator *operationCoordinator
 }
 
 type serviceResourceModel struct {
@@ -37,6 +72,7 @@ type serviceResourceModel struct {
 	ServicePackageActivationMode types.String `tfsdk:"service_package_activation_mode"`
 	ServiceDnsName               types.String `tfsdk:"service_dns_name"`
 	ForceRemove                  types.Bool   `tfsdk:"force_remove"`
+	DeletionProtection           types.Bool   `tfsdk:"deletion_protection"`
 	Partition                    types.Object `tfsdk:"partition"`
 	Stateless                    types.Object `tfsdk:"stateless"`
 	Stateful                     types.Object `tfsdk:"stateful"`
@@ -44,6 +80,10 @@ type serviceResourceModel struct {
 	ServiceStatus                types.String `tfsdk:"service_status"`
 }
 
+type serviceIdentityModel struct {
+	Name types.String `tfsdk:"name"`
+}
+
 type partitionModel struct {
 	Scheme  types.String `tfsdk:"scheme"`
 	Count   types.Int64  `tfsdk:"count"`
@@ -78,6 +118,17 @@ func (r *serviceResource) Metadata(_ context.Context, req resource.MetadataReque
 	resp.TypeName = req.ProviderTypeName + "_service"
 }
 
+func (r *serviceResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"name": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Fully-qualified Service Fabric service name, e.g. fabric:/App/Service.",
+			},
+		},
+	}
+}
+
 func (r *serviceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = rschema.Schema{
 		Attributes: map[string]rschema.Attribute{
@@ -148,6 +199,12 @@ func (r *serviceResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				Default:     booldefault.StaticBool(false),
 				Description: "Forcefully delete the service without graceful shutdown.",
 			},
+			"deletion_protection": rschema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "When true, destroying or replacing the service fails. Set to false and apply before destroying it.",
+			},
 			"health_state": rschema.StringAttribute{
 				Computed:    true,
 				Description: "Current health state reported by the cluster.",
@@ -262,6 +319,196 @@ func (r *serviceResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 	r.client = data.Client
+	r.coordinator = data.Coordinator
+}
+
+func (r *serviceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		checkDeletionProtection(ctx, req.State, &resp.Diagnostics, "service")
+		return
+	}
+	var plan serviceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var uses []clusterFeatureUse
+	if dnsName, ok := stringValue(plan.ServiceDnsName); ok && dnsName != "" {
+		uses = append(uses, clusterFeatureUse{path.Root("service_dns_name"), servicefabric.FeatureServiceDNSName})
+	}
+	stateless, diags := decodeStatelessModel(ctx, plan.Stateless)
+	resp.Diagnostics.Append(diags...)
+	if stateless != nil {
+		statelessPath := path.Root("stateless")
+		if !stateless.MinInstanceCount.IsNull() {
+			uses = append(uses, clusterFeatureUse{statelessPath.AtName("min_instance_count"), servicefabric.FeatureMinInstanceCount})
+		}
+		if !stateless.MinInstancePercentage.IsNull() {
+			uses = append(uses, clusterFeatureUse{statelessPath.AtName("min_instance_percentage"), servicefabric.FeatureMinInstanceCount})
+		}
+		if !stateless.InstanceCloseDelaySeconds.IsNull() {
+			uses = append(uses, clusterFeatureUse{statelessPath.AtName("instance_close_delay_seconds"), servicefabric.FeatureInstanceCloseDelayDurationSeconds})
+		}
+		if !stateless.InstanceRestartWaitSeconds.IsNull() {
+			uses = append(uses, clusterFeatureUse{statelessPath.AtName("instance_restart_wait_seconds"), servicefabric.FeatureInstanceRestartWaitDurationSeconds})
+		}
+	}
+	stateful, diags := decodeStatefulModel(ctx, plan.Stateful)
+	resp.Diagnostics.Append(diags...)
+	if stateful != nil && !stateful.ServicePlacementTimeLimitSeconds.IsNull() {
+		uses = append(uses, clusterFeatureUse{path.Root("stateful").AtName("service_placement_time_limit_seconds"), servicefabric.FeatureServicePlacementTimeLimitSeconds})
+	}
+	resp.Diagnostics.Append(checkClusterFeatures(r.client, uses)...)
+
+	var state *serviceResourceModel
+	if !req.State.Raw.IsNull() {
+		state = &serviceResourceModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	r.checkServiceType(ctx, plan, stateful, state, resp)
+}
+
+// ValidateConfig ensures the kind-specific block matches service_kind.
+func (r *serviceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config serviceResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	kind, ok := stringValue(config.ServiceKind)
+	if !ok {
+		return
+	}
+
+	switch canonicalServiceKind(kind) {
+	case "Stateless":
+		if !config.Stateful.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("stateful"), "Unexpected stateful configuration", "The stateful block cannot be set when service_kind is Stateless.")
+		}
+		if config.Stateless.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("stateless"), "Missing stateless configuration", "The stateless block must be set when service_kind is Stateless.")
+		}
+	case "Stateful":
+		if !config.Stateless.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("stateless"), "Unexpected stateless configuration", "The stateless block cannot be set when service_kind is Stateful.")
+		}
+		if config.Stateful.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("stateful"), "Missing stateful configuration", "The stateful block must be set when service_kind is Stateful.")
+			return
+		}
+		stateful, diags := decodeStatefulModel(ctx, config.Stateful)
+		resp.Diagnostics.Append(diags...)
+		if stateful == nil {
+			return
+		}
+		statefulPath := path.Root("stateful")
+		for _, setting := range []struct {
+			name  string
+			value attr.Value
+		}{
+			{"target_replica_set_size", stateful.TargetReplicaSetSize},
+			{"min_replica_set_size", stateful.MinReplicaSetSize},
+			{"has_persisted_state", stateful.HasPersistedState},
+		} {
+			if setting.value.IsNull() {
+				resp.Diagnostics.AddAttributeError(statefulPath.AtName(setting.name), "Missing stateful setting", fmt.Sprintf("%s must be set for stateful services.", setting.name))
+			}
+		}
+		target, okTarget := int64Value(stateful.TargetReplicaSetSize)
+		minimum, okMin := int64Value(stateful.MinReplicaSetSize)
+		if okTarget && okMin && minimum > target {
+			resp.Diagnostics.AddAttributeError(statefulPath.AtName("min_replica_set_size"), "Invalid replica set size", fmt.Sprintf("min_replica_set_size (%d) cannot exceed target_replica_set_size (%d).", minimum, target))
+		}
+	}
+}
+
+// checkServiceType validates a service that is about to be created, either new
+// or replacing the one in state, against the service type declared by the
+// application's current type version. Services whose application does not
+// exist yet are checked during apply instead.
+func (r *serviceResource) checkServiceType(ctx context.Context, plan serviceResourceModel, stateful *statefulServiceModel, state *serviceResourceModel, resp *resource.ModifyPlanResponse) {
+	if r.client == nil || (state != nil && !serviceReplaced(plan, *state)) {
+		return
+	}
+	appName, ok := stringValue(plan.ApplicationName)
+	if !ok {
+		return
+	}
+	serviceTypeName, ok := stringValue(plan.ServiceTypeName)
+	if !ok {
+		return
+	}
+
+	app, err := r.client.GetApplication(ctx, appName)
+	if err != nil {
+		if !servicefabric.IsNotFoundError(err) {
+			resp.Diagnostics.AddWarning("Unable to verify service type", fmt.Sprintf("Looking up application %s failed: %s", appName, err))
+		}
+		return
+	}
+	info, err := r.client.GetServiceType(ctx, app.TypeName, app.TypeVersion, serviceTypeName)
+	if err != nil {
+		if servicefabric.IsNotFoundError(err) {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("service_type_name"),
+				"Service type not declared",
+				fmt.Sprintf("Application type %s version %s, which %s currently runs, does not declare service type %s. Creating the service fails unless the application is upgraded to a version declaring it first.", app.TypeName, app.TypeVersion, appName, serviceTypeName),
+			)
+			return
+		}
+		resp.Diagnostics.AddWarning("Unable to verify service type", fmt.Sprintf("Looking up service type %s failed: %s", serviceTypeName, err))
+		return
+	}
+
+	details := extractServiceTypeDetails(*info)
+	if details.IsServiceGroup {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("service_type_name"),
+			"Service group type",
+			fmt.Sprintf("Service type %s is declared as a service group and cannot be created as a single service. Use servicefabric_service_group instead.", serviceTypeName),
+		)
+		return
+	}
+	kind, _ := stringValue(plan.ServiceKind)
+	if details.Kind != "" && kind != "" && !strings.EqualFold(details.Kind, kind) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("service_kind"),
+			"Service kind does not match service type",
+			fmt.Sprintf("Service type %s in application type %s version %s is %s, but service_kind is %s.", serviceTypeName, app.TypeName, app.TypeVersion, details.Kind, kind),
+		)
+		return
+	}
+	if stateful != nil && details.HasPersistedState != nil && !*details.HasPersistedState {
+		if persisted, ok := boolValue(stateful.HasPersistedState); ok && persisted {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("stateful").AtName("has_persisted_state"),
+				"Service type has no persisted state",
+				fmt.Sprintf("Service type %s does not declare HasPersistedState, so has_persisted_state cannot be true.", serviceTypeName),
+			)
+		}
+	}
+	if constraints, ok := stringValue(plan.PlacementConstraints); ok && details.PlacementConstraints != "" && strings.TrimSpace(constraints) != strings.TrimSpace(details.PlacementConstraints) {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("placement_constraints"),
+			"Service type declares placement constraints",
+			fmt.Sprintf("Service type %s declares the placement constraints %q, which apply in addition to %q. Make sure nodes satisfy both.", serviceTypeName, details.PlacementConstraints, constraints),
+		)
+	}
+}
+
+// serviceReplaced reports whether plan changes an attribute that forces the
+// service to be recreated.
+func serviceReplaced(plan, state serviceResourceModel) bool {
+	return !plan.Name.Equal(state.Name) ||
+		!plan.ApplicationName.Equal(state.ApplicationName) ||
+		!plan.ServiceTypeName.Equal(state.ServiceTypeName) ||
+		!plan.ServiceKind.Equal(state.ServiceKind) ||
+		!plan.ServicePackageActivationMode.Equal(state.ServicePackageActivationMode) ||
+		!plan.Partition.Equal(state.Partition)
 }
 
 func (r *serviceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -277,7 +524,11 @@ func (r *serviceResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	if err := r.client.CreateService(ctx, desc); err != nil {
+	appName := applicationNameForModel(plan)
+	err := r.coordinator.runServiceOperation(ctx, r.client, appName, func() error {
+		return r.client.CreateService(ctx, desc)
+	})
+	if err != nil {
 		resp.Diagnostics.AddError("Failed to create service", err.Error())
 		return
 	}
@@ -290,7 +541,6 @@ func (r *serviceResource) Create(ctx context.Context, req resource.CreateRequest
 		plan.ServiceStatus = types.StringNull()
 	}
 
-	appName := applicationNameForModel(plan)
 	info, err := r.client.GetService(ctx, appName, plan.Name.ValueString())
 	if err == nil {
 		r.applyInfoToState(&plan, info)
@@ -300,6 +550,7 @@ func (r *serviceResource) Create(ctx context.Context, req resource.CreateRequest
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(setServiceIdentity(ctx, resp.Identity, plan)...)
 }
 
 func (r *serviceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -319,9 +570,38 @@ func (r *serviceResource) Read(ctx context.Context, req resource.ReadRequest, re
 		resp.Diagnostics.AddError("Failed to read service", err.Error())
 		return
 	}
+	if info.IsServiceGroup {
+		resp.Diagnostics.AddError(
+			"Service is a service group",
+			fmt.Sprintf("%s is a service group. Manage it with servicefabric_service_group instead.", info.Name),
+		)
+		return
+	}
 
 	r.applyInfoToState(&state, info)
+
+	// Imported services only carry the name, so populate the configuration
+	// from the description the service was created with.
+	if state.Partition.IsNull() {
+		desc, err := r.client.GetServiceDescription(ctx, state.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read service description", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(applyServiceDescriptionToState(ctx, &state, desc)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if state.ForceRemove.IsNull() {
+		state.ForceRemove = types.BoolValue(false)
+	}
+	if state.DeletionProtection.IsNull() {
+		state.DeletionProtection = types.BoolValue(false)
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(setServiceIdentity(ctx, resp.Identity, state)...)
 }
 
 func (r *serviceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -339,14 +619,17 @@ func (r *serviceResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	appName := applicationNameForModel(plan)
 	if changed {
-		if err := r.client.UpdateService(ctx, plan.Name.ValueString(), updateDesc); err != nil {
+		err := r.coordinator.runServiceOperation(ctx, r.client, appName, func() error {
+			return r.client.UpdateService(ctx, plan.Name.ValueString(), updateDesc)
+		})
+		if err != nil {
 			resp.Diagnostics.AddError("Failed to update service", err.Error())
 			return
 		}
 	}
 
-	appName := applicationNameForModel(plan)
 	info, err := r.client.GetService(ctx, appName, plan.Name.ValueString())
 	if err == nil {
 		r.applyInfoToState(&plan, info)
@@ -356,6 +639,7 @@ func (r *serviceResource) Update(ctx context.Context, req resource.UpdateRequest
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(setServiceIdentity(ctx, resp.Identity, plan)...)
 }
 
 func (r *serviceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -365,8 +649,17 @@ func (r *serviceResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	checkDeletionProtection(ctx, req.State, &resp.Diagnostics, "service")
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	forceDelete, _ := boolValue(state.ForceRemove)
-	if err := r.client.DeleteService(ctx, state.Name.ValueString(), forceDelete); err != nil {
+	appName := applicationNameForModel(state)
+	err := r.coordinator.runServiceOperation(ctx, r.client, appName, func() error {
+		return r.client.DeleteService(ctx, appName, state.Name.ValueString(), forceDelete)
+	})
+	if err != nil {
 		if servicefabric.IsNotFoundError(err) {
 			return
 		}
@@ -375,6 +668,31 @@ func (r *serviceResource) Delete(ctx context.Context, req resource.DeleteRequest
 	}
 }
 
+func (r *serviceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	name := strings.TrimSpace(req.ID)
+	if name == "" && req.Identity != nil {
+		var identity serviceIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		name = strings.TrimSpace(identity.Name.ValueString())
+	}
+	if name == "" {
+		resp.Diagnostics.AddError("Missing identifier", "Import requires a service name such as fabric:/App/Service.")
+		return
+	}
+	appName, err := deriveApplicationNameFromService(name)
+	if err != nil {
+		resp.Diagnostics.AddError("Unexpected import identifier", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("application_name"), appName)...)
+}
+
 func (r *serviceResource) applyInfoToState(state *serviceResourceModel, info *servicefabric.ServiceInfo) {
 	state.ID = types.StringValue(info.Name)
 	state.Name = types.StringValue(info.Name)
@@ -625,6 +943,98 @@ func (r *serviceResource) buildUpdateDescription(ctx context.Context, plan servi
 	}
 }
 
+func applyServiceDescriptionToState(ctx context.Context, state *serviceResourceModel, desc *servicefabric.ServiceDescriptionInfo) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if desc.ServiceTypeName != "" {
+		state.ServiceTypeName = types.StringValue(desc.ServiceTypeName)
+	}
+	if kind := canonicalServiceKind(desc.ServiceKind); kind != "" {
+		state.ServiceKind = types.StringValue(kind)
+	}
+	if desc.PlacementConstraints != "" {
+		state.PlacementConstraints = types.StringValue(desc.PlacementConstraints)
+	}
+	if desc.DefaultMoveCost != "" {
+		state.DefaultMoveCost = types.StringValue(desc.DefaultMoveCost)
+	}
+	if desc.ServicePackageActivationMode != "" {
+		state.ServicePackageActivationMode = types.StringValue(desc.ServicePackageActivationMode)
+	}
+	if desc.ServiceDnsName != "" {
+		state.ServiceDnsName = types.StringValue(desc.ServiceDnsName)
+	}
+
+	partition := desc.PartitionDescription
+	names := types.ListNull(types.StringType)
+	if len(partition.Names) > 0 {
+		list, listDiags := types.ListValueFrom(ctx, types.StringType, partition.Names)
+		diags.Append(listDiags...)
+		names = list
+	}
+	partitionValue, partitionDiags := types.ObjectValue(partitionAttrTypes, map[string]attr.Value{
+		"scheme":   types.StringValue(canonicalPartitionScheme(partition.PartitionScheme)),
+		"count":    int64StringValue(partition.Count),
+		"names":    names,
+		"low_key":  int64StringValue(partition.LowKey),
+		"high_key": int64StringValue(partition.HighKey),
+	})
+	diags.Append(partitionDiags...)
+	if diags.HasError() {
+		return diags
+	}
+	if canonicalPartitionScheme(partition.PartitionScheme) == "" {
+		partitionValue = types.ObjectNull(partitionAttrTypes)
+	}
+	state.Partition = partitionValue
+
+	switch canonicalServiceKind(desc.ServiceKind) {
+	case "Stateless":
+		stateless, statelessDiags := types.ObjectValue(statelessServiceAttrTypes, map[string]attr.Value{
+			"instance_count":                int64StringValue(desc.InstanceCount),
+			"min_instance_count":            positiveInt64StringValue(desc.MinInstanceCount),
+			"min_instance_percentage":       positiveInt64StringValue(desc.MinInstancePercentage),
+			"instance_close_delay_seconds":  positiveInt64StringValue(desc.InstanceCloseDelayDurationSeconds),
+			"instance_restart_wait_seconds": positiveInt64StringValue(desc.InstanceRestartWaitDurationSeconds),
+		})
+		diags.Append(statelessDiags...)
+		state.Stateless = stateless
+		state.Stateful = types.ObjectNull(statefulServiceAttrTypes)
+	case "Stateful":
+		hasPersisted := types.BoolNull()
+		if desc.HasPersistedState != nil {
+			hasPersisted = types.BoolValue(*desc.HasPersistedState)
+		}
+		stateful, statefulDiags := types.ObjectValue(statefulServiceAttrTypes, map[string]attr.Value{
+			"target_replica_set_size":              int64StringValue(desc.TargetReplicaSetSize),
+			"min_replica_set_size":                 int64StringValue(desc.MinReplicaSetSize),
+			"has_persisted_state":                  hasPersisted,
+			"replica_restart_wait_seconds":         positiveInt64StringValue(desc.ReplicaRestartWaitDurationSeconds),
+			"quorum_loss_wait_seconds":             positiveInt64StringValue(desc.QuorumLossWaitDurationSeconds),
+			"standby_replica_keep_seconds":         positiveInt64StringValue(desc.StandByReplicaKeepDurationSeconds),
+			"service_placement_time_limit_seconds": positiveInt64StringValue(desc.ServicePlacementTimeLimitSeconds),
+		})
+		diags.Append(statefulDiags...)
+		state.Stateful = stateful
+		state.Stateless = types.ObjectNull(statelessServiceAttrTypes)
+	}
+	return diags
+}
+
+func int64StringValue(v *servicefabric.Int64String) types.Int64 {
+	if v == nil {
+		return types.Int64Null()
+	}
+	return types.Int64Value(int64(*v))
+}
+
+func positiveInt64StringValue(v *servicefabric.Int64String) types.Int64 {
+	if v == nil || *v <= 0 {
+		return types.Int64Null()
+	}
+	return types.Int64Value(int64(*v))
+}
+
 func expandPartitionDescription(ctx context.Context, value types.Object) (*servicefabric.PartitionDescription, diag.Diagnostics) {
 	var diags diag.Diagnostics
 	var model partitionModel
@@ -770,3 +1180,12 @@ func applicationNameForModel(model serviceResourceModel) string {
 	}
 	return appName
 }
+
+func setServiceIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, state serviceResourceModel) diag.Diagnostics {
+	if identity == nil {
+		return nil
+	}
+	return identity.Set(ctx, serviceIdentityModel{
+		Name: state.Name,
+	})
+}
@@ -0,0 +1,922 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	stringplanmodifier "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/williamoconnorme/terraform-provider-servicefabric/internal/servicefabric"
+)
+
+var _ resource.Resource = &serviceGroupResource{}
+var _ resource.ResourceWithIdentity = &serviceGroupResource{}
+var _ resource.ResourceWithImportState = &serviceGroupResource{}
+var _ resource.ResourceWithModifyPlan = &serviceGroupResource{}
+var _ resource.ResourceWithValidateConfig = &serviceGroupResource{}
+
+var (
+	serviceGroupStatelessAttrTypes = map[string]attr.Type{
+		"instance_count": types.Int64Type,
+	}
+	serviceGroupStatefulAttrTypes = map[string]attr.Type{
+		"target_replica_set_size":      types.Int64Type,
+		"min_replica_set_size":         types.Int64Type,
+		"has_persisted_state":          types.BoolType,
+		"replica_restart_wait_seconds": types.Int64Type,
+		"quorum_loss_wait_seconds":     types.Int64Type,
+		"standby_replica_keep_seconds": types.Int64Type,
+	}
+
+	serviceGroupMemberNameRegex = regexp.MustCompile(`^[^#/]+$`)
+)
+
+type serviceGroupResource struct {
+	client      *servicefabric.Client
+	coordinator *operationCoordinator
+}
+
+type serviceGroupResourceModel struct {
+	ID                   types.String              `tfsdk:"id"`
+	Name                 types.String              `tfsdk:"name"`
+	ApplicationName      types.String              `tfsdk:"application_name"`
+	ServiceTypeName      types.String              `tfsdk:"service_type_name"`
+	ServiceKind          types.String              `tfsdk:"service_kind"`
+	PlacementConstraints types.String              `tfsdk:"placement_constraints"`
+	DeletionProtection   types.Bool                `tfsdk:"deletion_protection"`
+	Members              []serviceGroupMemberModel `tfsdk:"members"`
+	Partition            types.Object              `tfsdk:"partition"`
+	Stateless            types.Object              `tfsdk:"stateless"`
+	Stateful             types.Object              `tfsdk:"stateful"`
+	HealthState          types.String              `tfsdk:"health_state"`
+	ServiceStatus        types.String              `tfsdk:"service_status"`
+}
+
+type serviceGroupMemberModel struct {
+	Name            types.String             `tfsdk:"name"`
+	ServiceTypeName types.String             `tfsdk:"service_type_name"`
+	LoadMetrics     []serviceLoadMetricModel `tfsdk:"load_metrics"`
+}
+
+type serviceLoadMetricModel struct {
+	Name                 types.String `tfsdk:"name"`
+	Weight               types.String `tfsdk:"weight"`
+	PrimaryDefaultLoad   types.Int64  `tfsdk:"primary_default_load"`
+	SecondaryDefaultLoad types.Int64  `tfsdk:"secondary_default_load"`
+	DefaultLoad          types.Int64  `tfsdk:"default_load"`
+}
+
+type serviceGroupStatelessModel struct {
+	InstanceCount types.Int64 `tfsdk:"instance_count"`
+}
+
+type serviceGroupStatefulModel struct {
+	TargetReplicaSetSize      types.Int64 `tfsdk:"target_replica_set_size"`
+	MinReplicaSetSize         types.Int64 `tfsdk:"min_replica_set_size"`
+	HasPersistedState         types.Bool  `tfsdk:"has_persisted_state"`
+	ReplicaRestartWaitSeconds types.Int64 `tfsdk:"replica_restart_wait_seconds"`
+	QuorumLossWaitSeconds     types.Int64 `tfsdk:"quorum_loss_wait_seconds"`
+	StandByReplicaKeepSeconds types.Int64 `tfsdk:"standby_replica_keep_seconds"`
+}
+
+func NewServiceGroupResource() resource.Resource {
+	return &serviceGroupResource{}
+}
+
+func (r *serviceGroupResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service_group"
+}
+
+func (r *serviceGroupResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"name": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Fully-qualified service group name, e.g. fabric:/App/Group.",
+			},
+		},
+	}
+}
+
+func (r *serviceGroupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = rschema.Schema{
+		Description: "Manages a Service Fabric service group: member services created, placed and partitioned together.",
+		Attributes: map[string]rschema.Attribute{
+			"id": rschema.StringAttribute{
+				Computed:      true,
+				Description:   "Unique identifier for the service group (Service Fabric name).",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"name": rschema.StringAttribute{
+				Required:    true,
+				Description: "Fully-qualified service group name, e.g. fabric:/App/Group.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"application_name": rschema.StringAttribute{
+				Required:    true,
+				Description: "Service Fabric application that owns the service group.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"service_type_name": rschema.StringAttribute{
+				Required:    true,
+				Description: "Service group type declared in the application manifest.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"service_kind": rschema.StringAttribute{
+				Required:    true,
+				Description: "Service kind of the group and all its members. Supported values: Stateful, Stateless.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("Stateful", "Stateless"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"placement_constraints": rschema.StringAttribute{
+				Optional:    true,
+				Description: "Node placement constraints applied to the service group.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"deletion_protection": rschema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "When true, destroying or replacing the service group fails. Set to false and apply before destroying it.",
+			},
+			"health_state": rschema.StringAttribute{
+				Computed:    true,
+				Description: "Current health state reported by the cluster.",
+			},
+			"service_status": rschema.StringAttribute{
+				Computed:    true,
+				Description: "Provisioning status reported by the cluster.",
+			},
+			"members": rschema.ListNestedAttribute{
+				Required:    true,
+				Description: "Member services of the group. Members cannot be changed after creation.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+				NestedObject: rschema.NestedAttributeObject{
+					Attributes: map[string]rschema.Attribute{
+						"name": rschema.StringAttribute{
+							Required:    true,
+							Description: "Member name within the group. The member's full name is {name of the group}#{name}.",
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(serviceGroupMemberNameRegex, "must be the member name without the service group name"),
+							},
+						},
+						"service_type_name": rschema.StringAttribute{
+							Required:    true,
+							Description: "Service type of the member.",
+						},
+						"load_metrics": rschema.ListNestedAttribute{
+							Optional:    true,
+							Description: "Load metrics the member reports for resource balancing.",
+							NestedObject: rschema.NestedAttributeObject{
+								Attributes: map[string]rschema.Attribute{
+									"name": rschema.StringAttribute{
+										Required:    true,
+										Description: "Metric name.",
+									},
+									"weight": rschema.StringAttribute{
+										Optional:    true,
+										Description: "Weight of the metric relative to other metrics. Allowed values: Zero, Low, Medium, High.",
+										Validators: []validator.String{
+											stringvalidator.OneOf("Zero", "Low", "Medium", "High"),
+										},
+									},
+									"primary_default_load": rschema.Int64Attribute{
+										Optional:    true,
+										Description: "Default load of a primary replica (stateful groups).",
+									},
+									"secondary_default_load": rschema.Int64Attribute{
+										Optional:    true,
+										Description: "Default load of a secondary replica (stateful groups).",
+									},
+									"default_load": rschema.Int64Attribute{
+										Optional:    true,
+										Description: "Default load of an instance (stateless groups).",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"partition": rschema.SingleNestedAttribute{
+				Required:    true,
+				Description: "Partitioning settings shared by all members of the group.",
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: map[string]rschema.Attribute{
+					"scheme": rschema.StringAttribute{
+						Required:    true,
+						Description: "Partition scheme. Supported values: Singleton, UniformInt64Range, Named.",
+						Validators: []validator.String{
+							stringvalidator.OneOf("Singleton", "UniformInt64Range", "Named"),
+						},
+					},
+					"count": rschema.Int64Attribute{
+						Optional:    true,
+						Description: "Partition count for Named or UniformInt64Range schemes.",
+					},
+					"names": rschema.ListAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+						Description: "Partition names when using the Named scheme.",
+					},
+					"low_key": rschema.Int64Attribute{
+						Optional:    true,
+						Description: "Low key for UniformInt64Range partitions.",
+					},
+					"high_key": rschema.Int64Attribute{
+						Optional:    true,
+						Description: "High key for UniformInt64Range partitions.",
+					},
+				},
+			},
+			"stateless": rschema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Stateless service group configuration. Required when service_kind is Stateless.",
+				Attributes: map[string]rschema.Attribute{
+					"instance_count": rschema.Int64Attribute{
+						Optional:    true,
+						Description: "Number of instances per partition (-1 deploys to every node).",
+					},
+				},
+			},
+			"stateful": rschema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Stateful service group configuration. Required when service_kind is Stateful.",
+				Attributes: map[string]rschema.Attribute{
+					"target_replica_set_size": rschema.Int64Attribute{
+						Optional:    true,
+						Description: "Number of replicas for each partition.",
+					},
+					"min_replica_set_size": rschema.Int64Attribute{
+						Optional:    true,
+						Description: "Minimum replicas required for quorum.",
+					},
+					"has_persisted_state": rschema.BoolAttribute{
+						Optional:    true,
+						Description: "Indicates whether the members persist state. Changing this value forces a new resource.",
+						PlanModifiers: []planmodifier.Bool{
+							boolplanmodifier.RequiresReplace(),
+						},
+					},
+					"replica_restart_wait_seconds": rschema.Int64Attribute{
+						Optional:    true,
+						Description: "Wait duration (seconds) before restarting a failed replica.",
+					},
+					"quorum_loss_wait_seconds": rschema.Int64Attribute{
+						Optional:    true,
+						Description: "Duration (seconds) to wait before declaring quorum loss.",
+					},
+					"standby_replica_keep_seconds": rschema.Int64Attribute{
+						Optional:    true,
+						Description: "Time (seconds) to keep standby replicas in the cluster.",
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *serviceGroupResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok || data == nil {
+		return
+	}
+	r.client = data.Client
+	r.coordinator = data.Coordinator
+}
+
+// ValidateConfig ensures the kind-specific block matches service_kind and that
+// member names are unique.
+func (r *serviceGroupResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config serviceGroupResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	seen := map[string]bool{}
+	for i, member := range config.Members {
+		name, ok := stringValue(member.Name)
+		if !ok {
+			continue
+		}
+		if seen[name] {
+			resp.Diagnostics.AddAttributeError(path.Root("members").AtListIndex(i).AtName("name"), "Duplicate member name", fmt.Sprintf("Member %q is declared more than once.", name))
+		}
+		seen[name] = true
+	}
+
+	kind, ok := stringValue(config.ServiceKind)
+	if !ok {
+		return
+	}
+	switch canonicalServiceKind(kind) {
+	case "Stateless":
+		if !config.Stateful.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("stateful"), "Unexpected stateful configuration", "The stateful block cannot be set when service_kind is Stateless.")
+		}
+		if config.Stateless.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("stateless"), "Missing stateless configuration", "The stateless block must be set when service_kind is Stateless.")
+		}
+	case "Stateful":
+		if !config.Stateless.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("stateless"), "Unexpected stateless configuration", "The stateless block cannot be set when service_kind is Stateful.")
+		}
+		if config.Stateful.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("stateful"), "Missing stateful configuration", "The stateful block must be set when service_kind is Stateful.")
+			return
+		}
+		stateful, diags := decodeServiceGroupStatefulModel(ctx, config.Stateful)
+		resp.Diagnostics.Append(diags...)
+		if stateful == nil {
+			return
+		}
+		statefulPath := path.Root("stateful")
+		for _, setting := range []struct {
+			name  string
+			value attr.Value
+		}{
+			{"target_replica_set_size", stateful.TargetReplicaSetSize},
+			{"min_replica_set_size", stateful.MinReplicaSetSize},
+			{"has_persisted_state", stateful.HasPersistedState},
+		} {
+			if setting.value.IsNull() {
+				resp.Diagnostics.AddAttributeError(statefulPath.AtName(setting.name), "Missing stateful setting", fmt.Sprintf("%s must be set for stateful service groups.", setting.name))
+			}
+		}
+		target, okTarget := int64Value(stateful.TargetReplicaSetSize)
+		minimum, okMin := int64Value(stateful.MinReplicaSetSize)
+		if okTarget && okMin && minimum > target {
+			resp.Diagnostics.AddAttributeError(statefulPath.AtName("min_replica_set_size"), "Invalid replica set size", fmt.Sprintf("min_replica_set_size (%d) cannot exceed target_replica_set_size (%d).", minimum, target))
+		}
+	}
+}
+
+func (r *serviceGroupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		checkDeletionProtection(ctx, req.State, &resp.Diagnostics, "service group")
+		return
+	}
+	if !req.State.Raw.IsNull() {
+		return
+	}
+	var plan serviceGroupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.checkServiceGroupType(ctx, plan, resp)
+}
+
+// checkServiceGroupType rejects a new service group whose type is declared as
+// a plain service type. Groups whose application does not exist yet are
+// checked during apply instead.
+func (r *serviceGroupResource) checkServiceGroupType(ctx context.Context, plan serviceGroupResourceModel, resp *resource.ModifyPlanResponse) {
+	if r.client == nil {
+		return
+	}
+	appName, ok := stringValue(plan.ApplicationName)
+	if !ok {
+		return
+	}
+	serviceTypeName, ok := stringValue(plan.ServiceTypeName)
+	if !ok {
+		return
+	}
+
+	app, err := r.client.GetApplication(ctx, appName)
+	if err != nil {
+		if !servicefabric.IsNotFoundError(err) {
+			resp.Diagnostics.AddWarning("Unable to verify service group type", fmt.Sprintf("Looking up application %s failed: %s", appName, err))
+		}
+		return
+	}
+	info, err := r.client.GetServiceType(ctx, app.TypeName, app.TypeVersion, serviceTypeName)
+	if err != nil {
+		if servicefabric.IsNotFoundError(err) {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("service_type_name"),
+				"Service group type not declared",
+				fmt.Sprintf("Application type %s version %s, which %s currently runs, does not declare service group type %s. Creating the service group fails unless the application is upgraded to a version declaring it first.", app.TypeName, app.TypeVersion, appName, serviceTypeName),
+			)
+			return
+		}
+		resp.Diagnostics.AddWarning("Unable to verify service group type", fmt.Sprintf("Looking up service type %s failed: %s", serviceTypeName, err))
+		return
+	}
+	if !extractServiceTypeDetails(*info).IsServiceGroup {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("service_type_name"),
+			"Not a service group type",
+			fmt.Sprintf("Service type %s is not declared as a service group. Use servicefabric_service to create it.", serviceTypeName),
+		)
+	}
+}
+
+func (r *serviceGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan serviceGroupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	desc, diags := expandServiceGroupDescription(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appName := desc.ApplicationName
+	err := r.coordinator.runServiceOperation(ctx, r.client, appName, func() error {
+		return r.client.CreateServiceGroup(ctx, *desc)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create service group", err.Error())
+		return
+	}
+
+	plan.ID = plan.Name
+	plan.HealthState = types.StringNull()
+	plan.ServiceStatus = types.StringNull()
+	info, err := r.client.GetService(ctx, appName, plan.Name.ValueString())
+	if err == nil {
+		applyServiceGroupInfoToState(&plan, info)
+	} else if !servicefabric.IsNotFoundError(err) {
+		resp.Diagnostics.AddError("Failed to read service group after creation", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(setServiceGroupIdentity(ctx, resp.Identity, plan)...)
+}
+
+func (r *serviceGroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state serviceGroupResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	info, err := r.client.GetService(ctx, state.ApplicationName.ValueString(), state.Name.ValueString())
+	if err != nil {
+		if servicefabric.IsNotFoundError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read service group", err.Error())
+		return
+	}
+	if !info.IsServiceGroup {
+		resp.Diagnostics.AddError(
+			"Service is not a service group",
+			fmt.Sprintf("%s is not a service group. Manage it with servicefabric_service instead.", info.Name),
+		)
+		return
+	}
+	applyServiceGroupInfoToState(&state, info)
+
+	// Imported service groups only carry the name, so populate the
+	// configuration from the description the group was created with.
+	if state.Partition.IsNull() {
+		desc, err := r.client.GetServiceGroupDescription(ctx, state.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read service group description", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(applyServiceGroupDescriptionToState(ctx, &state, desc)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if state.DeletionProtection.IsNull() {
+		state.DeletionProtection = types.BoolValue(false)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(setServiceGroupIdentity(ctx, resp.Identity, state)...)
+}
+
+func (r *serviceGroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan serviceGroupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateDesc, changed, diags := buildServiceGroupUpdateDescription(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appName := plan.ApplicationName.ValueString()
+	if changed {
+		err := r.coordinator.runServiceOperation(ctx, r.client, appName, func() error {
+			return r.client.UpdateServiceGroup(ctx, plan.Name.ValueString(), *updateDesc)
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update service group", err.Error())
+			return
+		}
+	}
+
+	info, err := r.client.GetService(ctx, appName, plan.Name.ValueString())
+	if err == nil {
+		applyServiceGroupInfoToState(&plan, info)
+	} else if !servicefabric.IsNotFoundError(err) {
+		resp.Diagnostics.AddError("Failed to refresh service group state", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(setServiceGroupIdentity(ctx, resp.Identity, plan)...)
+}
+
+func (r *serviceGroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state serviceGroupResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	checkDeletionProtection(ctx, req.State, &resp.Diagnostics, "service group")
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appName := state.ApplicationName.ValueString()
+	err := r.coordinator.runServiceOperation(ctx, r.client, appName, func() error {
+		return r.client.DeleteServiceGroup(ctx, appName, state.Name.ValueString())
+	})
+	if err != nil {
+		if servicefabric.IsNotFoundError(err) {
+			return
+		}
+		resp.Diagnostics.AddError("Failed to delete service group", err.Error())
+	}
+}
+
+func (r *serviceGroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	name := strings.TrimSpace(req.ID)
+	if name == "" && req.Identity != nil {
+		var identity serviceIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		name = strings.TrimSpace(identity.Name.ValueString())
+	}
+	if name == "" {
+		resp.Diagnostics.AddError("Missing identifier", "Import requires a service group name such as fabric:/App/Group.")
+		return
+	}
+	appName, err := deriveApplicationNameFromService(name)
+	if err != nil {
+		resp.Diagnostics.AddError("Unexpected import identifier", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("application_name"), appName)...)
+}
+
+func applyServiceGroupInfoToState(state *serviceGroupResourceModel, info *servicefabric.ServiceInfo) {
+	state.ID = types.StringValue(info.Name)
+	state.Name = types.StringValue(info.Name)
+	if appName, err := deriveApplicationNameFromService(info.Name); err == nil {
+		state.ApplicationName = types.StringValue(appName)
+	}
+	if info.TypeName != "" {
+		state.ServiceTypeName = types.StringValue(info.TypeName)
+	}
+	if kind := serviceKindFromInfo(*info); kind != "" {
+		state.ServiceKind = types.StringValue(kind)
+	}
+	state.HealthState = types.StringNull()
+	if info.HealthState != "" {
+		state.HealthState = types.StringValue(info.HealthState)
+	}
+	state.ServiceStatus = types.StringNull()
+	if info.ServiceStatus != "" {
+		state.ServiceStatus = types.StringValue(info.ServiceStatus)
+	}
+}
+
+func applyServiceGroupDescriptionToState(ctx context.Context, state *serviceGroupResourceModel, desc *servicefabric.ServiceGroupDescriptionInfo) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if desc.ServiceTypeName != "" {
+		state.ServiceTypeName = types.StringValue(desc.ServiceTypeName)
+	}
+	if kind := canonicalServiceKind(desc.ServiceKind); kind != "" {
+		state.ServiceKind = types.StringValue(kind)
+	}
+	if desc.PlacementConstraints != "" {
+		state.PlacementConstraints = types.StringValue(desc.PlacementConstraints)
+	}
+
+	groupPrefix := state.Name.ValueString() + "#"
+	state.Members = make([]serviceGroupMemberModel, 0, len(desc.ServiceGroupMemberDescription))
+	for _, member := range desc.ServiceGroupMemberDescription {
+		model := serviceGroupMemberModel{
+			Name:            types.StringValue(strings.TrimPrefix(member.ServiceName, groupPrefix)),
+			ServiceTypeName: types.StringValue(member.ServiceTypeName),
+		}
+		for _, metric := range member.ServiceLoadMetrics {
+			weight := types.StringNull()
+			if metric.Weight != "" {
+				weight = types.StringValue(metric.Weight)
+			}
+			model.LoadMetrics = append(model.LoadMetrics, serviceLoadMetricModel{
+				Name:                 types.StringValue(metric.Name),
+				Weight:               weight,
+				PrimaryDefaultLoad:   int64StringValue(metric.PrimaryDefaultLoad),
+				SecondaryDefaultLoad: int64StringValue(metric.SecondaryDefaultLoad),
+				DefaultLoad:          int64StringValue(metric.DefaultLoad),
+			})
+		}
+		state.Members = append(state.Members, model)
+	}
+
+	partition := desc.PartitionDescription
+	names := types.ListNull(types.StringType)
+	if len(partition.Names) > 0 {
+		list, listDiags := types.ListValueFrom(ctx, types.StringType, partition.Names)
+		diags.Append(listDiags...)
+		names = list
+	}
+	partitionValue, partitionDiags := types.ObjectValue(partitionAttrTypes, map[string]attr.Value{
+		"scheme":   types.StringValue(canonicalPartitionScheme(partition.PartitionScheme)),
+		"count":    int64StringValue(partition.Count),
+		"names":    names,
+		"low_key":  int64StringValue(partition.LowKey),
+		"high_key": int64StringValue(partition.HighKey),
+	})
+	diags.Append(partitionDiags...)
+	if diags.HasError() {
+		return diags
+	}
+	if canonicalPartitionScheme(partition.PartitionScheme) == "" {
+		partitionValue = types.ObjectNull(partitionAttrTypes)
+	}
+	state.Partition = partitionValue
+
+	switch canonicalServiceKind(desc.ServiceKind) {
+	case "Stateless":
+		stateless, statelessDiags := types.ObjectValue(serviceGroupStatelessAttrTypes, map[string]attr.Value{
+			"instance_count": int64StringValue(desc.InstanceCount),
+		})
+		diags.Append(statelessDiags...)
+		state.Stateless = stateless
+		state.Stateful = types.ObjectNull(serviceGroupStatefulAttrTypes)
+	case "Stateful":
+		hasPersisted := types.BoolNull()
+		if desc.HasPersistedState != nil {
+			hasPersisted = types.BoolValue(*desc.HasPersistedState)
+		}
+		stateful, statefulDiags := types.ObjectValue(serviceGroupStatefulAttrTypes, map[string]attr.Value{
+			"target_replica_set_size":      int64StringValue(desc.TargetReplicaSetSize),
+			"min_replica_set_size":         int64StringValue(desc.MinReplicaSetSize),
+			"has_persisted_state":          hasPersisted,
+			"replica_restart_wait_seconds": positiveInt64StringValue(desc.ReplicaRestartWaitDurationSeconds),
+			"quorum_loss_wait_seconds":     positiveInt64StringValue(desc.QuorumLossWaitDurationSeconds),
+			"standby_replica_keep_seconds": positiveInt64StringValue(desc.StandByReplicaKeepDurationSeconds),
+		})
+		diags.Append(statefulDiags...)
+		state.Stateful = stateful
+		state.Stateless = types.ObjectNull(serviceGroupStatelessAttrTypes)
+	}
+	return diags
+}
+
+func expandServiceGroupDescription(ctx context.Context, plan serviceGroupResourceModel) (*servicefabric.ServiceGroupDescription, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if plan.Partition.IsNull() || plan.Partition.IsUnknown() {
+		diags.AddError("Missing partition configuration", "The partition block must be provided.")
+		return nil, diags
+	}
+	partitionDesc, partDiags := expandPartitionDescription(ctx, plan.Partition)
+	diags.Append(partDiags...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	serviceKind, _ := stringValue(plan.ServiceKind)
+	canonicalKind := canonicalServiceKind(serviceKind)
+	if canonicalKind == "" {
+		diags.AddError("Invalid service kind", "service_kind must be either Stateful or Stateless.")
+		return nil, diags
+	}
+
+	groupName := strings.TrimSpace(plan.Name.ValueString())
+	desc := &servicefabric.ServiceGroupDescription{
+		ServiceKind:          canonicalKind,
+		ApplicationName:      strings.TrimSpace(plan.ApplicationName.ValueString()),
+		ServiceName:          groupName,
+		ServiceTypeName:      strings.TrimSpace(plan.ServiceTypeName.ValueString()),
+		PartitionDescription: *partitionDesc,
+	}
+	if v, ok := stringValue(plan.PlacementConstraints); ok {
+		desc.PlacementConstraints = v
+	}
+	for _, member := range plan.Members {
+		memberDesc := servicefabric.ServiceGroupMemberDescription{
+			ServiceTypeName: strings.TrimSpace(member.ServiceTypeName.ValueString()),
+			ServiceName:     groupName + "#" + strings.TrimSpace(member.Name.ValueString()),
+		}
+		for _, metric := range member.LoadMetrics {
+			metricDesc := servicefabric.ServiceLoadMetricDescription{
+				Name: metric.Name.ValueString(),
+			}
+			if v, ok := stringValue(metric.Weight); ok {
+				metricDesc.Weight = v
+			}
+			if v, ok := int64Value(metric.PrimaryDefaultLoad); ok {
+				metricDesc.PrimaryDefaultLoad = &v
+			}
+			if v, ok := int64Value(metric.SecondaryDefaultLoad); ok {
+				metricDesc.SecondaryDefaultLoad = &v
+			}
+			if v, ok := int64Value(metric.DefaultLoad); ok {
+				metricDesc.DefaultLoad = &v
+			}
+			memberDesc.ServiceLoadMetrics = append(memberDesc.ServiceLoadMetrics, metricDesc)
+		}
+		desc.ServiceGroupMemberDescription = append(desc.ServiceGroupMemberDescription, memberDesc)
+	}
+	if len(desc.ServiceGroupMemberDescription) == 0 {
+		diags.AddError("Missing members", "A service group requires at least one member.")
+		return nil, diags
+	}
+
+	switch canonicalKind {
+	case "Stateless":
+		model, statelessDiags := decodeServiceGroupStatelessModel(ctx, plan.Stateless)
+		diags.Append(statelessDiags...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		if model == nil {
+			diags.AddError("Missing stateless configuration", "stateless block must be provided when service_kind is Stateless.")
+			return nil, diags
+		}
+		instanceCount := int64(-1)
+		if v, ok := int64Value(model.InstanceCount); ok {
+			instanceCount = v
+		}
+		desc.InstanceCount = &instanceCount
+	case "Stateful":
+		model, statefulDiags := decodeServiceGroupStatefulModel(ctx, plan.Stateful)
+		diags.Append(statefulDiags...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		if model == nil {
+			diags.AddError("Missing stateful configuration", "stateful block must be provided when service_kind is Stateful.")
+			return nil, diags
+		}
+		targetSize, okTarget := int64Value(model.TargetReplicaSetSize)
+		minSize, okMin := int64Value(model.MinReplicaSetSize)
+		hasPersisted, okPersisted := boolValue(model.HasPersistedState)
+		if !okTarget || !okMin || !okPersisted {
+			diags.AddError("Incomplete stateful configuration", "target_replica_set_size, min_replica_set_size, and has_persisted_state must be specified.")
+			return nil, diags
+		}
+		desc.TargetReplicaSetSize = &targetSize
+		desc.MinReplicaSetSize = &minSize
+		desc.HasPersistedState = &hasPersisted
+		desc.ReplicaRestartWaitDurationSeconds = secondsString(model.ReplicaRestartWaitSeconds)
+		desc.QuorumLossWaitDurationSeconds = secondsString(model.QuorumLossWaitSeconds)
+		desc.StandByReplicaKeepDurationSeconds = secondsString(model.StandByReplicaKeepSeconds)
+	}
+	return desc, diags
+}
+
+func buildServiceGroupUpdateDescription(ctx context.Context, plan serviceGroupResourceModel) (*servicefabric.ServiceGroupUpdateDescription, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	kind, _ := stringValue(plan.ServiceKind)
+	var flags uint32
+	desc := &servicefabric.ServiceGroupUpdateDescription{
+		ServiceKind: canonicalServiceKind(kind),
+	}
+
+	switch desc.ServiceKind {
+	case "Stateless":
+		model, statelessDiags := decodeServiceGroupStatelessModel(ctx, plan.Stateless)
+		diags.Append(statelessDiags...)
+		if diags.HasError() || model == nil {
+			return nil, false, diags
+		}
+		if v, ok := int64Value(model.InstanceCount); ok {
+			desc.InstanceCount = &v
+			flags |= 0x0001
+		}
+	case "Stateful":
+		model, statefulDiags := decodeServiceGroupStatefulModel(ctx, plan.Stateful)
+		diags.Append(statefulDiags...)
+		if diags.HasError() || model == nil {
+			return nil, false, diags
+		}
+		if v, ok := int64Value(model.TargetReplicaSetSize); ok {
+			desc.TargetReplicaSetSize = &v
+			flags |= 0x0001
+		}
+		if v, ok := int64Value(model.MinReplicaSetSize); ok {
+			desc.MinReplicaSetSize = &v
+			flags |= 0x0010
+		}
+		if str := secondsString(model.ReplicaRestartWaitSeconds); str != nil {
+			desc.ReplicaRestartWaitDurationSeconds = str
+			flags |= 0x0002
+		}
+		if str := secondsString(model.QuorumLossWaitSeconds); str != nil {
+			desc.QuorumLossWaitDurationSeconds = str
+			flags |= 0x0004
+		}
+		if str := secondsString(model.StandByReplicaKeepSeconds); str != nil {
+			desc.StandByReplicaKeepDurationSeconds = str
+			flags |= 0x0008
+		}
+	}
+	if flags == 0 {
+		return nil, false, diags
+	}
+	desc.Flags = strconv.FormatUint(uint64(flags), 10)
+	return desc, true, diags
+}
+
+func decodeServiceGroupStatelessModel(ctx context.Context, value types.Object) (*serviceGroupStatelessModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if value.IsNull() || value.IsUnknown() {
+		return nil, diags
+	}
+	var model serviceGroupStatelessModel
+	options := basetypes.ObjectAsOptions{UnhandledNullAsEmpty: true, UnhandledUnknownAsEmpty: true}
+	diags.Append(value.As(ctx, &model, options)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	return &model, diags
+}
+
+func decodeServiceGroupStatefulModel(ctx context.Context, value types.Object) (*serviceGroupStatefulModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if value.IsNull() || value.IsUnknown() {
+		return nil, diags
+	}
+	var model serviceGroupStatefulModel
+	options := basetypes.ObjectAsOptions{UnhandledNullAsEmpty: true, UnhandledUnknownAsEmpty: true}
+	diags.Append(value.As(ctx, &model, options)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	return &model, diags
+}
+
+func setServiceGroupIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, state serviceGroupResourceModel) diag.Diagnostics {
+	if identity == nil {
+		return nil
+	}
+	return identity.Set(ctx, serviceIdentityModel{
+		Name: state.Name,
+	})
+}
@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const telemetryServiceName = "terraform-provider-servicefabric"
+
+// SetupTelemetry installs a global OpenTelemetry tracer provider exporting
+// client spans over OTLP when the standard OTEL_* environment variables ask for
+// it. Without them tracing stays a no-op. The returned function flushes
+// pending spans and must be called before the process exits.
+func SetupTelemetry(ctx context.Context) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if !telemetryEnabled() {
+		return noop, nil
+	}
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch protocol := otlpTracesProtocol(); protocol {
+	case "grpc":
+		exporter, err = otlptracegrpc.New(ctx)
+	case "http/protobuf":
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return noop, fmt.Errorf("unsupported OTLP protocol %q", protocol)
+	}
+	if err != nil {
+		return noop, fmt.Errorf("create OTLP trace exporter: %w", err)
+	}
+
+	// Attributes from OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES take
+	// precedence over the default service name.
+	res, err := sdkresource.New(ctx,
+		sdkresource.WithAttributes(attribute.String("service.name", telemetryServiceName)),
+		sdkresource.WithTelemetrySDK(),
+		sdkresource.WithFromEnv(),
+	)
+	if err != nil {
+		return noop, fmt.Errorf("build OpenTelemetry resource: %w", err)
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	return tracerProvider.Shutdown, nil
+}
+
+// telemetryEnabled follows the OpenTelemetry SDK environment conventions:
+// OTEL_SDK_DISABLED and OTEL_TRACES_EXPORTER=none turn tracing off, and
+// otherwise an OTLP endpoint or OTEL_TRACES_EXPORTER=otlp turns it on.
+func telemetryEnabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	switch exporter := strings.ToLower(strings.TrimSpace(os.Getenv("OTEL_TRACES_EXPORTER"))); exporter {
+	case "":
+		return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+	case "otlp":
+		return true
+	default:
+		return false
+	}
+}
+
+func otlpTracesProtocol() string {
+	for _, name := range []string{"OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "OTEL_EXPORTER_OTLP_PROTOCOL"} {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			return strings.ToLower(v)
+		}
+	}
+	return "http/protobuf"
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var (
+	iso8601DurationRegex = regexp.MustCompile(`^P(?:\d+Y)?(?:\d+M)?(?:\d+W)?(?:\d+D)?(?:T(?:\d+H)?(?:\d+M)?(?:\d+(?:\.\d+)?S)?)?$`)
+	millisecondsRegex    = regexp.MustCompile(`^\d+$`)
+)
+
+// upgradeDurationValidator accepts the duration formats Service Fabric takes
+// for upgrade timeouts: an ISO 8601 duration such as PT5M, or a whole number
+// of milliseconds.
+type upgradeDurationValidator struct{}
+
+func (v upgradeDurationValidator) Description(_ context.Context) string {
+	return "value must be an ISO 8601 duration (e.g. PT5M) or a number of milliseconds"
+}
+
+func (v upgradeDurationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v upgradeDurationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if isUpgradeDuration(req.ConfigValue.ValueString()) {
+		return
+	}
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid duration",
+		fmt.Sprintf("Attribute %s %s, got %q.", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+	)
+}
+
+func isUpgradeDuration(value string) bool {
+	if millisecondsRegex.MatchString(value) {
+		return true
+	}
+	if value == "" || value == "P" || value[len(value)-1] == 'T' {
+		return false
+	}
+	return iso8601DurationRegex.MatchString(value)
+}
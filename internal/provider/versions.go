@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// compareApplicationTypeVersions orders application type versions semantically.
+// Dot separated numeric segments are compared as numbers and any other segment
+// case-insensitively; a pre-release suffix ("-beta") sorts before the release.
+func compareApplicationTypeVersions(a, b string) int {
+	aCore, aPre, aHasPre := strings.Cut(strings.TrimSpace(a), "-")
+	bCore, bPre, bHasPre := strings.Cut(strings.TrimSpace(b), "-")
+	if c := compareVersionSegments(strings.Split(aCore, "."), strings.Split(bCore, ".")); c != 0 {
+		return c
+	}
+	switch {
+	case aHasPre && !bHasPre:
+		return -1
+	case !aHasPre && bHasPre:
+		return 1
+	case aHasPre && bHasPre:
+		return compareVersionSegments(strings.Split(aPre, "."), strings.Split(bPre, "."))
+	}
+	return 0
+}
+
+func compareVersionSegments(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var as, bs string
+		if i < len(a) {
+			as = a[i]
+		}
+		if i < len(b) {
+			bs = b[i]
+		}
+		an, aErr := strconv.ParseUint(orZero(as), 10, 64)
+		bn, bErr := strconv.ParseUint(orZero(bs), 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(strings.ToLower(as), strings.ToLower(bs)); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+func orZero(segment string) string {
+	if segment == "" {
+		return "0"
+	}
+	return segment
+}
+
+// versionConstraint is a single comparison such as ">= 1.2" or "~> 2.3".
+type versionConstraint struct {
+	operator string
+	version  string
+}
+
+// parseVersionConstraints parses a comma separated list of constraints using
+// the Terraform operators =, !=, >, >=, <, <= and ~>.
+func parseVersionConstraints(raw string) ([]versionConstraint, error) {
+	var constraints []versionConstraint
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("empty constraint in %q", raw)
+		}
+		operator := "="
+		for _, op := range []string{"~>", ">=", "<=", "!=", ">", "<", "="} {
+			if strings.HasPrefix(part, op) {
+				operator = op
+				part = strings.TrimSpace(strings.TrimPrefix(part, op))
+				break
+			}
+		}
+		if part == "" {
+			return nil, fmt.Errorf("constraint %q is missing a version", raw)
+		}
+		if operator == "~>" {
+			core, _, _ := strings.Cut(part, "-")
+			for _, segment := range strings.Split(core, ".") {
+				if _, err := strconv.ParseUint(segment, 10, 64); err != nil {
+					return nil, fmt.Errorf("constraint %q: ~> requires a numeric version", raw)
+				}
+			}
+		}
+		constraints = append(constraints, versionConstraint{operator: operator, version: part})
+	}
+	return constraints, nil
+}
+
+// matchVersionConstraints reports whether version satisfies every constraint.
+func matchVersionConstraints(version string, constraints []versionConstraint) bool {
+	for _, c := range constraints {
+		cmp := compareApplicationTypeVersions(version, c.version)
+		var ok bool
+		switch c.operator {
+		case "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		case "~>":
+			// Pre-releases of the upper bound sort below it but are outside
+			// the range, so only the release part is compared with the bound.
+			core, _, _ := strings.Cut(strings.TrimSpace(version), "-")
+			ok = cmp >= 0 && compareApplicationTypeVersions(core, pessimisticUpperBound(c.version)) < 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// pessimisticUpperBound returns the exclusive upper bound of "~> version": the
+// second to last segment is incremented, so "2.3" yields "3" and "2.3.1" yields
+// "2.4".
+func pessimisticUpperBound(version string) string {
+	core, _, _ := strings.Cut(version, "-")
+	segments := strings.Split(core, ".")
+	if len(segments) > 1 {
+		segments = segments[:len(segments)-1]
+	}
+	last, _ := strconv.ParseUint(segments[len(segments)-1], 10, 64)
+	segments[len(segments)-1] = strconv.FormatUint(last+1, 10)
+	return strings.Join(segments, ".")
+}
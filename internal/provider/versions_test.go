@@ -0,0 +1,133 @@
+package provider
+
+import "testing"
+
+func TestCompareApplicationTypeVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0", "1.0.0", 0},
+		{" 1.2 ", "1.2", 0},
+		{"1.9.0", "1.10.0", -1},
+		{"2.0", "1.99.99", 1},
+		{"1.0.0-beta", "1.0.0", -1},
+		{"1.0.0", "1.0.0-rc.1", 1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0.0-rc.2", "1.0.0-rc.10", -1},
+		{"1.0.0-RC", "1.0.0-rc", 0},
+		{"1.0.1", "1.0.a", -1},
+		{"1.0.b", "1.0.a", 1},
+	}
+
+	for _, tt := range tests {
+		if got := compareApplicationTypeVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareApplicationTypeVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := compareApplicationTypeVersions(tt.b, tt.a); got != -tt.want {
+			t.Errorf("compareApplicationTypeVersions(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestParseVersionConstraints(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    []versionConstraint
+		wantErr bool
+	}{
+		{raw: "1.2.0", want: []versionConstraint{{"=", "1.2.0"}}},
+		{raw: ">= 1.2, < 2", want: []versionConstraint{{">=", "1.2"}, {"<", "2"}}},
+		{raw: "~>2.3", want: []versionConstraint{{"~>", "2.3"}}},
+		{raw: "!= 1.0-beta", want: []versionConstraint{{"!=", "1.0-beta"}}},
+		{raw: "", wantErr: true},
+		{raw: ">= 1.0,", wantErr: true},
+		{raw: ">=", wantErr: true},
+		{raw: "~> 1.x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseVersionConstraints(tt.raw)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseVersionConstraints(%q) succeeded, want error", tt.raw)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseVersionConstraints(%q) returned error: %s", tt.raw, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("parseVersionConstraints(%q) = %v, want %v", tt.raw, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("parseVersionConstraints(%q)[%d] = %v, want %v", tt.raw, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestMatchVersionConstraints(t *testing.T) {
+	tests := []struct {
+		constraints string
+		version     string
+		want        bool
+	}{
+		{"= 1.2.0", "1.2", true},
+		{"1.2.0", "1.2.1", false},
+		{"!= 1.2.0", "1.2.1", true},
+		{"!= 1.2.0", "1.2.0", false},
+		{"> 1.2", "1.2.1", true},
+		{"> 1.2", "1.2", false},
+		{">= 1.2", "1.2", true},
+		{">= 1.2", "1.2-beta", false},
+		{"< 2", "1.99", true},
+		{"< 2", "2.0-beta", true},
+		{"< 2", "2.0", false},
+		{"<= 2", "2.0.0", true},
+		{"<= 2", "2.0.1", false},
+		{">= 1.0, < 2.0", "1.5", true},
+		{">= 1.0, < 2.0", "2.1", false},
+		{"~> 2.3", "2.3", true},
+		{"~> 2.3", "2.9.4", true},
+		{"~> 2.3", "2.2.9", false},
+		{"~> 2.3", "3.0", false},
+		{"~> 2.3", "3.0-beta", false},
+		{"~> 2.3", "2.4-beta", true},
+		{"~> 2.3.1", "2.3.5", true},
+		{"~> 2.3.1", "2.3.0", false},
+		{"~> 2.3.1", "2.4.0", false},
+		{"~> 2.3.1", "2.4.0-rc.1", false},
+		{"~> 2", "2.5", true},
+		{"~> 2", "3.0", false},
+	}
+
+	for _, tt := range tests {
+		constraints, err := parseVersionConstraints(tt.constraints)
+		if err != nil {
+			t.Fatalf("parseVersionConstraints(%q) returned error: %s", tt.constraints, err)
+		}
+		if got := matchVersionConstraints(tt.version, constraints); got != tt.want {
+			t.Errorf("matchVersionConstraints(%q, %q) = %t, want %t", tt.version, tt.constraints, got, tt.want)
+		}
+	}
+}
+
+func TestPessimisticUpperBound(t *testing.T) {
+	tests := map[string]string{
+		"2":         "3",
+		"2.3":       "3",
+		"2.3.1":     "2.4",
+		"1.2.3.4":   "1.2.4",
+		"2.3.1-rc1": "2.4",
+	}
+	for version, want := range tests {
+		if got := pessimisticUpperBound(version); got != want {
+			t.Errorf("pessimisticUpperBound(%q) = %q, want %q", version, got, want)
+		}
+	}
+}
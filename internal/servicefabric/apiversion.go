@@ -0,0 +1,213 @@
+package servicefabric
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxKnownAPIVersion is the newest api-version whose request and response
+// shapes the client has been verified against.
+const maxKnownAPIVersion = "8.2"
+
+type operation string
+
+const (
+	opGetClusterVersion        operation = "GetClusterVersion"
+	opProvisionApplicationType operation = "ProvisionApplicationType"
+	opGetApplications          operation = "GetApplications"
+	opCreateApplication        operation = "CreateApplication"
+	opGetApplication           operation = "GetApplication"
+	opUpgradeApplication       operation = "UpgradeApplication"
+	opGetUpgradeProgress       operation = "GetUpgradeProgress"
+	opCreateService            operation = "CreateService"
+	opUpdateService            operation = "UpdateService"
+	opGetServiceDescription    operation = "GetServiceDescription"
+	opCreateServiceGroup       operation = "CreateServiceGroup"
+	opUpdateServiceGroup       operation = "UpdateServiceGroup"
+	opDeleteServiceGroup       operation = "DeleteServiceGroup"
+	opGetServiceGroupDesc      operation = "GetServiceGroupDescription"
+)
+
+// apiVersionRange is the minimum api-version an operation exists in and the
+// version the client prefers so that newer request fields are honoured.
+type apiVersionRange struct {
+	min       string
+	preferred string
+}
+
+// operationAPIVersions is the capability table used to pick an api-version per
+// operation. Every operation whose request or response carries a field listed
+// in featureAPIVersions must be here; operations not listed use the client's
+// base version.
+var operationAPIVersions = map[operation]apiVersionRange{
+	opGetClusterVersion:        {min: "6.4", preferred: "6.4"},
+	opProvisionApplicationType: {min: "6.2", preferred: "6.2"},
+	opGetApplications:          {min: "6.1", preferred: "7.0"},
+	opCreateApplication:        {min: "6.0", preferred: "7.0"},
+	opGetApplication:           {min: "6.0", preferred: "7.0"},
+	opUpgradeApplication:       {min: "6.0", preferred: "8.0"},
+	opGetUpgradeProgress:       {min: "6.0", preferred: "8.0"},
+	opCreateService:            {min: "6.0", preferred: "8.0"},
+	opUpdateService:            {min: "6.0", preferred: "8.0"},
+	opGetServiceDescription:    {min: "6.0", preferred: "8.0"},
+	opCreateServiceGroup:       {min: "6.0", preferred: "8.0"},
+	opUpdateServiceGroup:       {min: "6.0", preferred: "8.0"},
+	opDeleteServiceGroup:       {min: "6.0", preferred: "8.0"},
+	opGetServiceGroupDesc:      {min: "6.0", preferred: "8.0"},
+}
+
+// Feature names a request field that is only honoured by newer clusters.
+type Feature string
+
+const (
+	FeatureServiceDNSName                     Feature = "ServiceDnsName"
+	FeatureManagedApplicationIdentity         Feature = "ManagedApplicationIdentity"
+	FeatureMinInstanceCount                   Feature = "MinInstanceCount"
+	FeatureInstanceCloseDelayDurationSeconds  Feature = "InstanceCloseDelayDurationSeconds"
+	FeatureInstanceRestartWaitDurationSeconds Feature = "InstanceRestartWaitDurationSeconds"
+	FeatureServicePlacementTimeLimitSeconds   Feature = "ServicePlacementTimeLimitSeconds"
+)
+
+// featureAPIVersions lists the api-version, and therefore cluster runtime
+// version, each feature was introduced in.
+var featureAPIVersions = map[Feature]string{
+	FeatureServiceDNSName:                     "6.3",
+	FeatureManagedApplicationIdentity:         "7.0",
+	FeatureMinInstanceCount:                   "7.0",
+	FeatureInstanceCloseDelayDurationSeconds:  "7.0",
+	FeatureServicePlacementTimeLimitSeconds:   "7.0",
+	FeatureInstanceRestartWaitDurationSeconds: "8.0",
+}
+
+type clusterVersionResponse struct {
+	Version string `json:"Version"`
+}
+
+// NegotiateAPIVersion queries the cluster runtime version so later requests can
+// use the newest api-version both sides support. Clusters older than 6.4 do
+// not expose the version; the client then keeps its base api-version.
+func (c *Client) NegotiateAPIVersion(ctx context.Context) (_ string, err error) {
+	ctx, span := startSpan(ctx, "NegotiateAPIVersion")
+	defer func() { endSpan(span, err) }()
+
+	resp, err := c.doRequest(ctx, http.MethodGet, "/$/GetClusterVersion", c.apiVersionQuery(opGetClusterVersion), nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var version clusterVersionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&version); err != nil {
+		return "", fmt.Errorf("decode cluster version: %w", err)
+	}
+	if _, ok := runtimeAPIVersion(version.Version); !ok {
+		return "", fmt.Errorf("unrecognized cluster version %q", version.Version)
+	}
+	c.clusterVersion = version.Version
+	return version.Version, nil
+}
+
+// ClusterVersion returns the runtime version reported by the cluster, or an
+// empty string when it has not been negotiated.
+func (c *Client) ClusterVersion() string {
+	return c.clusterVersion
+}
+
+// CheckFeature returns an error when the negotiated cluster is known to be too
+// old for the given feature. Unknown cluster versions are assumed to support it.
+func (c *Client) CheckFeature(feature Feature) error {
+	required, ok := featureAPIVersions[feature]
+	if !ok {
+		return nil
+	}
+	clusterAPI, ok := runtimeAPIVersion(c.clusterVersion)
+	if !ok {
+		return nil
+	}
+	if compareAPIVersions(clusterAPI, required) < 0 {
+		return fmt.Errorf("%s requires Service Fabric %s or later, but the cluster runs %s", feature, required, c.clusterVersion)
+	}
+	return nil
+}
+
+// apiVersionFor picks the api-version for op: the preferred version capped at
+// what the cluster runtime supports, but never below the operation's minimum
+// or the client's base version.
+func (c *Client) apiVersionFor(op operation) string {
+	versions, ok := operationAPIVersions[op]
+	if !ok {
+		return c.apiVersion
+	}
+	chosen := versions.preferred
+	if clusterAPI, ok := runtimeAPIVersion(c.clusterVersion); ok {
+		if compareAPIVersions(chosen, clusterAPI) > 0 {
+			chosen = clusterAPI
+		}
+	} else {
+		chosen = c.apiVersion
+	}
+	if compareAPIVersions(chosen, versions.min) < 0 {
+		chosen = versions.min
+	}
+	if compareAPIVersions(chosen, c.apiVersion) < 0 {
+		chosen = c.apiVersion
+	}
+	return chosen
+}
+
+func (c *Client) apiVersionQuery(op operation) url.Values {
+	query := url.Values{}
+	query.Set("api-version", c.apiVersionFor(op))
+	return query
+}
+
+// runtimeAPIVersion converts a runtime version such as "7.2.457.9590" to the
+// api-version it supports, capped at maxKnownAPIVersion.
+func runtimeAPIVersion(runtime string) (string, bool) {
+	parts := strings.Split(strings.TrimSpace(runtime), ".")
+	if len(parts) < 2 {
+		return "", false
+	}
+	if _, err := strconv.Atoi(parts[0]); err != nil {
+		return "", false
+	}
+	if _, err := strconv.Atoi(parts[1]); err != nil {
+		return "", false
+	}
+	version := parts[0] + "." + parts[1]
+	if compareAPIVersions(version, maxKnownAPIVersion) > 0 {
+		return maxKnownAPIVersion, true
+	}
+	return version, true
+}
+
+// compareAPIVersions compares "major.minor" api-versions numerically.
+func compareAPIVersions(a, b string) int {
+	aMajor, aMinor := splitAPIVersion(a)
+	bMajor, bMinor := splitAPIVersion(b)
+	switch {
+	case aMajor != bMajor:
+		if aMajor < bMajor {
+			return -1
+		}
+		return 1
+	case aMinor != bMinor:
+		if aMinor < bMinor {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+func splitAPIVersion(v string) (int, int) {
+	majorStr, minorStr, _ := strings.Cut(v, ".")
+	major, _ := strconv.Atoi(majorStr)
+	minor, _ := strconv.Atoi(minorStr)
+	return major, minor
+}
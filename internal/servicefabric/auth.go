@@ -1,16 +1,24 @@
 package servicefabric
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
-	"golang.org/x/crypto/pkcs12"
 )
 
 // Authenticator configures the HTTP client and applies per-request credentials.
@@ -20,32 +28,55 @@ type Authenticator interface {
 }
 
 // CertificateAuthenticator implements TLS client certificate authentication.
+// Certificates are supplied per handshake so rotated files are picked up, and
+// when several candidates are configured the next one is tried whenever the
+// cluster rejects the active certificate.
 type CertificateAuthenticator struct {
-	cert tls.Certificate
-}
+	sources []*certificateSource
 
-// NewCertificateAuthenticator loads the certificate from a PKCS#12/PFX file.
-func NewCertificateAuthenticator(path string, password string) (Authenticator, error) {
-	raw, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
+	mu        sync.Mutex
+	active    int
+	transport *http.Transport
+	// pinned holds, per candidate, a transport that always presents that
+	// candidate, so a single request can be retried with it without
+	// switching the active certificate.
+	pinned []http.RoundTripper
+}
 
-	privateKey, certificate, err := pkcs12.Decode(raw, password)
-	if err != nil {
-		return nil, fmt.Errorf("pkcs12 decode: %w", err)
+// NewCertificateAuthenticator loads the primary certificate and any fallback
+// candidates, each from a PKCS#12/PFX or PEM file or from inline content. The
+// first candidate that has not expired is used initially.
+func NewCertificateAuthenticator(primary CertificateOptions, candidates ...CertificateOptions) (Authenticator, error) {
+	a := &CertificateAuthenticator{}
+	for i, opts := range append([]CertificateOptions{primary}, candidates...) {
+		source, err := newCertificateSource(opts)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			return nil, fmt.Errorf("candidate certificate %d: %w", i, err)
+		}
+		a.sources = append(a.sources, source)
 	}
-
-	cert := tls.Certificate{
-		Certificate: [][]byte{certificate.Raw},
-		PrivateKey:  privateKey,
-		Leaf:        certificate,
+	now := time.Now()
+	for i, source := range a.sources {
+		if source.cert.Leaf.NotAfter.After(now) {
+			a.active = i
+			break
+		}
 	}
+	return a, nil
+}
 
-	return &CertificateAuthenticator{cert: cert}, nil
+// Certificate returns the leaf of the certificate currently presented to the
+// cluster.
+func (c *CertificateAuthenticator) Certificate() *x509.Certificate {
+	cert, _ := c.currentSource().certificate()
+	return cert.Leaf
 }
 
-// ConfigureHTTPClient attaches the client certificate to the TLS configuration.
+// ConfigureHTTPClient attaches the client certificate to the TLS configuration
+// and, with fallback candidates, retries rejected requests with the next one.
 func (c *CertificateAuthenticator) ConfigureHTTPClient(client *http.Client) error {
 	transport, err := ensureTransport(client)
 	if err != nil {
@@ -54,7 +85,22 @@ func (c *CertificateAuthenticator) ConfigureHTTPClient(client *http.Client) erro
 	if transport.TLSClientConfig == nil {
 		transport.TLSClientConfig = &tls.Config{}
 	}
-	transport.TLSClientConfig.Certificates = []tls.Certificate{c.cert}
+	transport.TLSClientConfig.Certificates = nil
+	transport.TLSClientConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return c.currentSource().certificate()
+	}
+	if len(c.sources) > 1 {
+		c.transport = transport
+		c.pinned = make([]http.RoundTripper, len(c.sources))
+		for i, source := range c.sources {
+			pinned := transport.Clone()
+			pinned.TLSClientConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return source.certificate()
+			}
+			c.pinned[i] = pinned
+		}
+		client.Transport = &certificateFailoverTransport{auth: c, next: transport}
+	}
 	return nil
 }
 
@@ -63,6 +109,114 @@ func (c *CertificateAuthenticator) Apply(_ context.Context, _ *http.Request) err
 	return nil
 }
 
+func (c *CertificateAuthenticator) currentSource() *certificateSource {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sources[c.active]
+}
+
+// rotate switches to the candidate after rejected, unless another request has
+// already moved on, drops connections authenticated with the old one and
+// returns the now active candidate.
+func (c *CertificateAuthenticator) rotate(rejected int) int {
+	c.mu.Lock()
+	if c.active == rejected {
+		c.active = (rejected + 1) % len(c.sources)
+	}
+	active := c.active
+	c.mu.Unlock()
+	c.transport.CloseIdleConnections()
+	return active
+}
+
+// certificateErrorCodes are the Fabric error codes a 403 carries when the
+// gateway refuses the client certificate itself, as opposed to a trusted
+// certificate lacking permission for the operation (E_ACCESSDENIED).
+var certificateErrorCodes = map[string]bool{
+	"FABRIC_E_INVALID_CREDENTIALS":     true,
+	"FABRIC_E_INVALID_X509_THUMBPRINT": true,
+	"FABRIC_E_INVALID_SUBJECT_NAME":    true,
+}
+
+// certificateFailoverTransport retries a request with the next candidate
+// certificate when the cluster rejects the current one, trying each candidate
+// at most once per request.
+type certificateFailoverTransport struct {
+	auth *CertificateAuthenticator
+	next http.RoundTripper
+}
+
+func (t *certificateFailoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.auth.mu.Lock()
+	active := t.auth.active
+	t.auth.mu.Unlock()
+
+	resp, err := t.next.RoundTrip(req)
+	for attempt := 1; attempt < len(t.auth.sources); attempt++ {
+		var next http.RoundTripper
+		switch {
+		case handshakeRejected(err):
+			// The cluster aborted the handshake, so the certificate is not
+			// trusted at all; later requests use the next candidate too.
+			active = t.auth.rotate(active)
+			next = t.next
+		case certificateForbidden(resp):
+			// Only this request is retried. A 403 is scoped to one operation,
+			// so it never switches the certificate used by other requests.
+			active = (active + 1) % len(t.auth.sources)
+			next = t.auth.pinned[active]
+		default:
+			return resp, err
+		}
+
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		resp, err = next.RoundTrip(req)
+	}
+	return resp, err
+}
+
+// handshakeRejected reports whether the cluster aborted the TLS handshake with
+// an alert, which is how it refuses a client certificate it does not trust.
+func handshakeRejected(err error) bool {
+	var opErr *net.OpError
+	return err != nil && errors.As(err, &opErr) && opErr.Op == "remote error"
+}
+
+// certificateForbidden reports whether resp is a 403 whose Fabric error code
+// says the client certificate was refused. The body is restored for the
+// caller.
+func certificateForbidden(resp *http.Response) bool {
+	if resp == nil || resp.StatusCode != http.StatusForbidden || resp.Body == nil {
+		return false
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	var fabricErr struct {
+		Error struct {
+			Code string `json:"Code"`
+		} `json:"Error"`
+	}
+	return json.Unmarshal(body, &fabricErr) == nil && certificateErrorCodes[fabricErr.Error.Code]
+}
+
 // EntraOptions contains parameters for acquiring Entra ID tokens.
 type EntraOptions struct {
 	ClusterApplicationID  string
@@ -70,12 +224,22 @@ type EntraOptions struct {
 	ClientID              string
 	ClientSecret          string
 	DefaultCredentialType string
+	// Environment selects the Azure cloud: "public" (default),
+	// "usgovernment" or "china".
+	Environment string
+	// AuthorityHost overrides the Entra authority of Environment, e.g. for
+	// air-gapped clouds.
+	AuthorityHost string
+	// OIDCToken and OIDCTokenFilePath supply a federated identity token (from
+	// GitHub Actions, GitLab or Azure DevOps) exchanged as a client assertion.
+	// The file is re-read for every token request so refreshed tokens are used.
+	OIDCToken         string
+	OIDCTokenFilePath string
 }
 
 // EntraAuthenticator acquires bearer tokens using Azure Identity credentials.
 type EntraAuthenticator struct {
-	cred  azcore.TokenCredential
-	scope string
+	tokens *tokenCache
 }
 
 // NewEntraAuthenticator builds an Entra authenticator using default or explicit credentials.
@@ -83,65 +247,135 @@ func NewEntraAuthenticator(opts EntraOptions) (Authenticator, error) {
 	if opts.ClusterApplicationID == "" {
 		return nil, fmt.Errorf("cluster application id required")
 	}
+	if opts.OIDCToken != "" && opts.OIDCTokenFilePath != "" {
+		return nil, fmt.Errorf("oidc token and oidc token file path are mutually exclusive")
+	}
+	if opts.ClientSecret != "" && (opts.OIDCToken != "" || opts.OIDCTokenFilePath != "") {
+		return nil, fmt.Errorf("client secret and oidc token are mutually exclusive")
+	}
 
 	scope := fmt.Sprintf("%s/.default", opts.ClusterApplicationID)
 
-	var cred azcore.TokenCredential
-	var err error
-	if opts.ClientID != "" && opts.ClientSecret != "" {
-		cred, err = azidentity.NewClientSecretCredential(opts.TenantID, opts.ClientID, opts.ClientSecret, nil)
-	} else {
-		cred, err = buildDefaultAzureCredential(opts)
-	}
+	clientOptions, err := entraClientOptions(opts)
 	if err != nil {
 		return nil, err
 	}
 
+	var (
+		cred     azcore.TokenCredential
+		credName string
+	)
+	switch {
+	case opts.ClientID != "" && opts.ClientSecret != "":
+		credName = "ClientSecretCredential"
+		cred, err = azidentity.NewClientSecretCredential(opts.TenantID, opts.ClientID, opts.ClientSecret, &azidentity.ClientSecretCredentialOptions{
+			ClientOptions: clientOptions,
+		})
+	case opts.OIDCToken != "" || opts.OIDCTokenFilePath != "":
+		if opts.TenantID == "" || opts.ClientID == "" {
+			return nil, fmt.Errorf("oidc token authentication requires tenant id and client id")
+		}
+		credName = "ClientAssertionCredential (OIDC token)"
+		cred, err = azidentity.NewClientAssertionCredential(opts.TenantID, opts.ClientID, oidcAssertion(opts), &azidentity.ClientAssertionCredentialOptions{
+			ClientOptions: clientOptions,
+		})
+	default:
+		credName, cred, err = buildDefaultAzureCredential(opts, clientOptions)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create %s: %w", credName, err)
+	}
+
 	return &EntraAuthenticator{
-		cred:  cred,
-		scope: scope,
+		tokens: newTokenCache(cred, credName, scope),
 	}, nil
 }
 
-func buildDefaultAzureCredential(opts EntraOptions) (azcore.TokenCredential, error) {
+// entraClientOptions points credentials at the configured cloud.
+func entraClientOptions(opts EntraOptions) (azcore.ClientOptions, error) {
+	var options azcore.ClientOptions
+	switch strings.ToLower(opts.Environment) {
+	case "", "public":
+		options.Cloud = cloud.AzurePublic
+	case "usgovernment":
+		options.Cloud = cloud.AzureGovernment
+	case "china":
+		options.Cloud = cloud.AzureChina
+	default:
+		return options, fmt.Errorf("unsupported environment %q", opts.Environment)
+	}
+	if opts.AuthorityHost != "" {
+		options.Cloud.ActiveDirectoryAuthorityHost = opts.AuthorityHost
+	}
+	return options, nil
+}
+
+func oidcAssertion(opts EntraOptions) func(context.Context) (string, error) {
+	return func(context.Context) (string, error) {
+		if opts.OIDCTokenFilePath == "" {
+			return opts.OIDCToken, nil
+		}
+		raw, err := os.ReadFile(opts.OIDCTokenFilePath)
+		if err != nil {
+			return "", fmt.Errorf("read oidc token file: %w", err)
+		}
+		token := strings.TrimSpace(string(raw))
+		if token == "" {
+			return "", fmt.Errorf("oidc token file %s is empty", opts.OIDCTokenFilePath)
+		}
+		return token, nil
+	}
+}
+
+// buildDefaultAzureCredential returns the credential selected by
+// DefaultCredentialType together with a name used in error messages.
+func buildDefaultAzureCredential(opts EntraOptions, clientOptions azcore.ClientOptions) (string, azcore.TokenCredential, error) {
 	switch opts.DefaultCredentialType {
 	case "", "default":
-		options := &azidentity.DefaultAzureCredentialOptions{}
+		options := &azidentity.DefaultAzureCredentialOptions{ClientOptions: clientOptions}
 		if opts.TenantID != "" {
 			options.TenantID = opts.TenantID
 		}
-		return azidentity.NewDefaultAzureCredential(options)
+		cred, err := azidentity.NewDefaultAzureCredential(options)
+		return "DefaultAzureCredential", cred, err
 	case "environment":
-		return azidentity.NewEnvironmentCredential(nil)
+		cred, err := azidentity.NewEnvironmentCredential(&azidentity.EnvironmentCredentialOptions{ClientOptions: clientOptions})
+		return "EnvironmentCredential", cred, err
 	case "workload_identity":
 		options := &azidentity.WorkloadIdentityCredentialOptions{
-			ClientID: opts.ClientID,
-			TenantID: opts.TenantID,
+			ClientOptions: clientOptions,
+			ClientID:      opts.ClientID,
+			TenantID:      opts.TenantID,
 		}
-		return azidentity.NewWorkloadIdentityCredential(options)
+		cred, err := azidentity.NewWorkloadIdentityCredential(options)
+		return "WorkloadIdentityCredential", cred, err
 	case "managed_identity":
-		options := &azidentity.ManagedIdentityCredentialOptions{}
+		options := &azidentity.ManagedIdentityCredentialOptions{ClientOptions: clientOptions}
 		if opts.ClientID != "" {
 			options.ID = azidentity.ClientID(opts.ClientID)
 		}
-		return azidentity.NewManagedIdentityCredential(options)
+		cred, err := azidentity.NewManagedIdentityCredential(options)
+		return "ManagedIdentityCredential", cred, err
 	case "azure_cli":
 		options := &azidentity.AzureCLICredentialOptions{
 			TenantID: opts.TenantID,
 		}
-		return azidentity.NewAzureCLICredential(options)
+		cred, err := azidentity.NewAzureCLICredential(options)
+		return "AzureCLICredential", cred, err
 	case "azure_developer_cli":
 		options := &azidentity.AzureDeveloperCLICredentialOptions{
 			TenantID: opts.TenantID,
 		}
-		return azidentity.NewAzureDeveloperCLICredential(options)
+		cred, err := azidentity.NewAzureDeveloperCLICredential(options)
+		return "AzureDeveloperCLICredential", cred, err
 	case "azure_powershell":
 		options := &azidentity.AzurePowerShellCredentialOptions{
 			TenantID: opts.TenantID,
 		}
-		return azidentity.NewAzurePowerShellCredential(options)
+		cred, err := azidentity.NewAzurePowerShellCredential(options)
+		return "AzurePowerShellCredential", cred, err
 	default:
-		return nil, fmt.Errorf("unsupported credential type %q", opts.DefaultCredentialType)
+		return opts.DefaultCredentialType, nil, fmt.Errorf("unsupported credential type %q", opts.DefaultCredentialType)
 	}
 }
 
@@ -150,13 +384,11 @@ func (a *EntraAuthenticator) ConfigureHTTPClient(_ *http.Client) error {
 }
 
 func (a *EntraAuthenticator) Apply(ctx context.Context, req *http.Request) error {
-	token, err := a.cred.GetToken(ctx, policy.TokenRequestOptions{
-		Scopes: []string{a.scope},
-	})
+	token, err := a.tokens.token(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get token: %w", err)
+		return err
 	}
-	req.Header.Set("Authorization", "Bearer "+token.Token)
+	req.Header.Set("Authorization", "Bearer "+token)
 	return nil
 }
 
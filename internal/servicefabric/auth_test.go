@@ -0,0 +1,140 @@
+package servicefabric
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// recordingTransport answers every request with respond and counts calls.
+type recordingTransport struct {
+	calls   int
+	respond func() (*http.Response, error)
+}
+
+func (r *recordingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	r.calls++
+	return r.respond()
+}
+
+func fabricResponse(status int, code string) func() (*http.Response, error) {
+	return func() (*http.Response, error) {
+		body := ""
+		if code != "" {
+			body = `{"Error":{"Code":"` + code + `","Message":"denied"}}`
+		}
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}
+}
+
+func newFailoverTestAuthenticator(t *testing.T) *CertificateAuthenticator {
+	t.Helper()
+	opts := CertificateOptions{Path: testdataPath("client.pem"), KeyPath: testdataPath("client.key")}
+	auth, err := NewCertificateAuthenticator(opts, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth.(*CertificateAuthenticator)
+}
+
+func TestCertificateFailoverTransport(t *testing.T) {
+	tests := []struct {
+		name          string
+		primary       func() (*http.Response, error)
+		wantStatus    int
+		wantPinned    int
+		wantNextCalls int
+		wantActive    int
+	}{
+		{
+			name:          "success",
+			primary:       fabricResponse(http.StatusOK, ""),
+			wantStatus:    http.StatusOK,
+			wantNextCalls: 1,
+		},
+		{
+			name:          "403 for an operation the certificate may not perform",
+			primary:       fabricResponse(http.StatusForbidden, "E_ACCESSDENIED"),
+			wantStatus:    http.StatusForbidden,
+			wantNextCalls: 1,
+		},
+		{
+			name:          "401 without certificate error code",
+			primary:       fabricResponse(http.StatusUnauthorized, ""),
+			wantStatus:    http.StatusUnauthorized,
+			wantNextCalls: 1,
+		},
+		{
+			name:          "403 with certificate error code retries only the request",
+			primary:       fabricResponse(http.StatusForbidden, "FABRIC_E_INVALID_CREDENTIALS"),
+			wantStatus:    http.StatusOK,
+			wantNextCalls: 1,
+			wantPinned:    1,
+		},
+		{
+			name: "handshake alert switches the active certificate",
+			primary: func() func() (*http.Response, error) {
+				calls := 0
+				return func() (*http.Response, error) {
+					calls++
+					if calls == 1 {
+						return nil, &net.OpError{Op: "remote error", Err: errors.New("tls: bad certificate")}
+					}
+					return fabricResponse(http.StatusOK, "")()
+				}
+			}(),
+			wantStatus:    http.StatusOK,
+			wantNextCalls: 2,
+			wantActive:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := newFailoverTestAuthenticator(t)
+			auth.transport = &http.Transport{}
+			pinned := &recordingTransport{respond: fabricResponse(http.StatusOK, "")}
+			auth.pinned = []http.RoundTripper{&recordingTransport{respond: fabricResponse(http.StatusOK, "")}, pinned}
+			next := &recordingTransport{respond: tt.primary}
+			transport := &certificateFailoverTransport{auth: auth, next: next}
+
+			req, err := http.NewRequest(http.MethodGet, "https://cluster:19080/Applications", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() returned error: %s", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if next.calls != tt.wantNextCalls {
+				t.Errorf("active transport called %d times, want %d", next.calls, tt.wantNextCalls)
+			}
+			if pinned.calls != tt.wantPinned {
+				t.Errorf("candidate transport called %d times, want %d", pinned.calls, tt.wantPinned)
+			}
+			if auth.active != tt.wantActive {
+				t.Errorf("active candidate = %d, want %d", auth.active, tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestCertificateForbiddenRestoresBody(t *testing.T) {
+	resp, _ := fabricResponse(http.StatusForbidden, "E_ACCESSDENIED")()
+	if certificateForbidden(resp) {
+		t.Fatal("E_ACCESSDENIED treated as a certificate rejection")
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "E_ACCESSDENIED") {
+		t.Errorf("body = %q, want the original response body", body)
+	}
+}
@@ -0,0 +1,102 @@
+package servicefabric
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultListCacheTTL bounds how long list responses are shared. The cache lives
+// as long as the provider process, which for a long apply can be hours, so a
+// response may be this stale after a change made outside Terraform.
+const defaultListCacheTTL = 30 * time.Second
+
+// listCache shares list responses between resources read close together, such
+// as during a refresh. Concurrent lookups for the same key wait for the first
+// one instead of issuing duplicate requests. Any write made through the client
+// clears it.
+type listCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]*listCacheEntry
+}
+
+type listCacheEntry struct {
+	done    chan struct{}
+	value   any
+	err     error
+	expires time.Time
+}
+
+func newListCache(ttl time.Duration) *listCache {
+	return &listCache{
+		ttl:     ttl,
+		entries: map[string]*listCacheEntry{},
+	}
+}
+
+// get returns the cached value for key or calls load to populate it. Failed
+// loads are not cached; callers waiting on one retry the load themselves.
+func (c *listCache) get(ctx context.Context, key string, load func() (any, error)) (any, error) {
+	if c == nil || c.ttl <= 0 || bypassListCache(ctx) {
+		return load()
+	}
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if entry.err == nil && time.Now().Before(entry.expires) {
+			return entry.value, nil
+		}
+		c.mu.Lock()
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+		return c.get(ctx, key, load)
+	}
+	entry := &listCacheEntry{done: make(chan struct{})}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	entry.value, entry.err = load()
+	entry.expires = time.Now().Add(c.ttl)
+	close(entry.done)
+
+	if entry.err != nil {
+		c.mu.Lock()
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+	}
+	return entry.value, entry.err
+}
+
+// invalidate drops every cached response.
+func (c *listCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.entries = map[string]*listCacheEntry{}
+	c.mu.Unlock()
+}
+
+type bypassListCacheKey struct{}
+
+// withoutListCache marks ctx so list lookups always reach the cluster, as
+// required by pollers waiting for a state change.
+func withoutListCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassListCacheKey{}, true)
+}
+
+func bypassListCache(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassListCacheKey{}).(bool)
+	return bypass
+}
@@ -0,0 +1,320 @@
+package servicefabric
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"strings"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// CertificateOptions describes where a client certificate is loaded from.
+// Exactly one of Path and Content must be set.
+type CertificateOptions struct {
+	// Path is a PKCS#12/PFX file or a PEM file holding the certificate chain
+	// and, unless KeyPath is set, the private key.
+	Path string
+	// Content is the base64-encoded content of a PFX or PEM file. PEM text is
+	// also accepted as-is.
+	Content string
+	// KeyPath is a PEM private key used with a PEM certificate.
+	KeyPath string
+	// Password decrypts a PFX file or an encrypted PKCS#8 private key.
+	Password string
+}
+
+// loadClientCertificate reads and parses the certificate described by opts.
+func loadClientCertificate(opts CertificateOptions) (tls.Certificate, error) {
+	var (
+		certData []byte
+		err      error
+	)
+	switch {
+	case opts.Path != "" && opts.Content != "":
+		return tls.Certificate{}, errors.New("certificate path and inline content are mutually exclusive")
+	case opts.Path != "":
+		certData, err = os.ReadFile(opts.Path)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+	case opts.Content != "":
+		certData, err = decodeInlineCertificate(opts.Content)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+	default:
+		return tls.Certificate{}, errors.New("no client certificate configured")
+	}
+
+	var keyData []byte
+	if opts.KeyPath != "" {
+		keyData, err = os.ReadFile(opts.KeyPath)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+	}
+	return parseClientCertificate(certData, keyData, opts.Password)
+}
+
+func decodeInlineCertificate(content string) ([]byte, error) {
+	if strings.Contains(content, "-----BEGIN ") {
+		return []byte(content), nil
+	}
+	cleaned := strings.Join(strings.Fields(content), "")
+	data, err := base64.StdEncoding.DecodeString(cleaned)
+	if err != nil {
+		return nil, fmt.Errorf("decode inline certificate: %w", err)
+	}
+	return data, nil
+}
+
+// parseClientCertificate accepts either a PFX blob or PEM data. For PEM the
+// private key may live in certData or keyData. The returned certificate holds
+// the full chain, leaf first, so intermediates are sent in the handshake.
+func parseClientCertificate(certData, keyData []byte, password string) (tls.Certificate, error) {
+	if !isPEM(certData) {
+		if len(keyData) > 0 {
+			return tls.Certificate{}, errors.New("a separate private key can only be used with a PEM certificate")
+		}
+		privateKey, leaf, caCerts, err := pkcs12.DecodeChain(certData, password)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("pkcs12 decode: %w", err)
+		}
+		return buildCertificate(privateKey, append([]*x509.Certificate{leaf}, caCerts...))
+	}
+
+	var (
+		certs []*x509.Certificate
+		keys  []crypto.PrivateKey
+	)
+	for _, data := range [][]byte{certData, keyData} {
+		for rest := data; ; {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			switch block.Type {
+			case "CERTIFICATE":
+				cert, err := x509.ParseCertificate(block.Bytes)
+				if err != nil {
+					return tls.Certificate{}, fmt.Errorf("parse certificate: %w", err)
+				}
+				certs = append(certs, cert)
+			case "PRIVATE KEY", "RSA PRIVATE KEY", "EC PRIVATE KEY", "ENCRYPTED PRIVATE KEY":
+				key, err := parsePrivateKey(block, password)
+				if err != nil {
+					return tls.Certificate{}, err
+				}
+				keys = append(keys, key)
+			}
+		}
+	}
+	switch {
+	case len(certs) == 0:
+		return tls.Certificate{}, errors.New("no certificate found in PEM data")
+	case len(keys) == 0:
+		return tls.Certificate{}, errors.New("no private key found in PEM data")
+	case len(keys) > 1:
+		return tls.Certificate{}, errors.New("more than one private key found in PEM data")
+	}
+
+	// The leaf is whichever certificate belongs to the key; the remaining
+	// certificates keep their file order as the chain.
+	for i, cert := range certs {
+		if publicKeyMatches(cert, keys[0]) {
+			chain := append([]*x509.Certificate{cert}, certs[:i]...)
+			chain = append(chain, certs[i+1:]...)
+			return buildCertificate(keys[0], chain)
+		}
+	}
+	return tls.Certificate{}, errors.New("private key does not match any certificate in PEM data")
+}
+
+func buildCertificate(privateKey crypto.PrivateKey, chain []*x509.Certificate) (tls.Certificate, error) {
+	if !publicKeyMatches(chain[0], privateKey) {
+		return tls.Certificate{}, errors.New("private key does not match the certificate")
+	}
+	cert := tls.Certificate{
+		PrivateKey: privateKey,
+		Leaf:       chain[0],
+	}
+	for _, c := range chain {
+		cert.Certificate = append(cert.Certificate, c.Raw)
+	}
+	return cert, nil
+}
+
+func publicKeyMatches(cert *x509.Certificate, privateKey crypto.PrivateKey) bool {
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return false
+	}
+	public, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	return ok && public.Equal(cert.PublicKey)
+}
+
+func isPEM(data []byte) bool {
+	return bytes.Contains(data, []byte("-----BEGIN "))
+}
+
+func parsePrivateKey(block *pem.Block, password string) (crypto.PrivateKey, error) {
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		if _, encrypted := block.Headers["DEK-Info"]; encrypted {
+			return nil, errors.New("legacy encrypted PEM keys are not supported; convert the key to encrypted PKCS#8")
+		}
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		if _, encrypted := block.Headers["DEK-Info"]; encrypted {
+			return nil, errors.New("legacy encrypted PEM keys are not supported; convert the key to encrypted PKCS#8")
+		}
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "ENCRYPTED PRIVATE KEY":
+		if password == "" {
+			return nil, errors.New("private key is encrypted but no password was provided")
+		}
+		var der []byte
+		der, err = decryptPKCS8(block.Bytes, password)
+		if err != nil {
+			return nil, err
+		}
+		key, err = x509.ParsePKCS8PrivateKey(der)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+	return key, nil
+}
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC     = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// decryptPKCS8 decrypts a PBES2 (PBKDF2 with AES-CBC or 3DES-CBC) encrypted
+// PKCS#8 key, the format written by `openssl pkcs8 -topk8` and most secret
+// managers.
+func decryptPKCS8(der []byte, password string) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("parse encrypted private key: %w", err)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("unsupported private key encryption %s; only PBES2 is supported", info.Algorithm.Algorithm)
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("parse PBES2 parameters: %w", err)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("unsupported key derivation function %s", params.KeyDerivationFunc.Algorithm)
+	}
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, fmt.Errorf("parse PBKDF2 parameters: %w", err)
+	}
+
+	var prf func() hash.Hash
+	switch alg := kdf.PRF.Algorithm; {
+	case len(alg) == 0, alg.Equal(oidHMACWithSHA1):
+		prf = sha1.New
+	case alg.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	case alg.Equal(oidHMACWithSHA384):
+		prf = sha512.New384
+	case alg.Equal(oidHMACWithSHA512):
+		prf = sha512.New
+	default:
+		return nil, fmt.Errorf("unsupported PBKDF2 pseudo-random function %s", alg)
+	}
+
+	var (
+		keyLength int
+		newCipher func([]byte) (cipher.Block, error)
+	)
+	switch alg := params.EncryptionScheme.Algorithm; {
+	case alg.Equal(oidAES128CBC):
+		keyLength, newCipher = 16, aes.NewCipher
+	case alg.Equal(oidAES192CBC):
+		keyLength, newCipher = 24, aes.NewCipher
+	case alg.Equal(oidAES256CBC):
+		keyLength, newCipher = 32, aes.NewCipher
+	case alg.Equal(oidDESEDE3CBC):
+		keyLength, newCipher = 24, des.NewTripleDESCipher
+	default:
+		return nil, fmt.Errorf("unsupported private key cipher %s", alg)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, fmt.Errorf("parse cipher parameters: %w", err)
+	}
+
+	key, err := pbkdf2.Key(prf, password, kdf.Salt, kdf.IterationCount, keyLength)
+	if err != nil {
+		return nil, err
+	}
+	block, err := newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != block.BlockSize() || len(info.EncryptedData) == 0 || len(info.EncryptedData)%block.BlockSize() != 0 {
+		return nil, errors.New("malformed encrypted private key")
+	}
+	plain := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, info.EncryptedData)
+
+	padding := int(plain[len(plain)-1])
+	if padding == 0 || padding > block.BlockSize() || !bytes.Equal(plain[len(plain)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, errors.New("decrypt private key: incorrect password")
+	}
+	return plain[:len(plain)-padding], nil
+}
@@ -0,0 +1,84 @@
+package servicefabric
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// certificateCheckInterval bounds how often the certificate files are
+	// checked for changes.
+	certificateCheckInterval = 30 * time.Second
+	// certificateRenewBefore is how close to expiry a certificate must be before
+	// its files are re-read even though they appear unchanged.
+	certificateRenewBefore = 24 * time.Hour
+)
+
+// certificateSource holds a client certificate and reloads it when its files
+// change or it nears expiry, so long applies survive certificate rotation.
+// Inline content cannot change during a run and is parsed once.
+type certificateSource struct {
+	opts CertificateOptions
+
+	mu        sync.Mutex
+	cert      tls.Certificate
+	stamp     string
+	checkedAt time.Time
+}
+
+func newCertificateSource(opts CertificateOptions) (*certificateSource, error) {
+	s := &certificateSource{opts: opts}
+	stamp, _ := s.fileStamp()
+	cert, err := loadClientCertificate(opts)
+	if err != nil {
+		return nil, err
+	}
+	s.cert, s.stamp, s.checkedAt = cert, stamp, time.Now()
+	return s, nil
+}
+
+// certificate returns the current certificate, reloading it first if needed.
+// A failed reload keeps serving the previously loaded certificate.
+func (s *certificateSource) certificate() (*tls.Certificate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if (s.opts.Path == "" && s.opts.KeyPath == "") || time.Since(s.checkedAt) < certificateCheckInterval {
+		return &s.cert, nil
+	}
+	s.checkedAt = time.Now()
+
+	stamp, err := s.fileStamp()
+	if err != nil {
+		return &s.cert, nil
+	}
+	if stamp == s.stamp && time.Until(s.cert.Leaf.NotAfter) > certificateRenewBefore {
+		return &s.cert, nil
+	}
+	cert, err := loadClientCertificate(s.opts)
+	if err != nil {
+		return &s.cert, nil
+	}
+	s.cert, s.stamp = cert, stamp
+	return &s.cert, nil
+}
+
+// fileStamp summarises the modification time and size of the certificate and
+// key files so rewrites can be detected without re-parsing them.
+func (s *certificateSource) fileStamp() (string, error) {
+	var stamp string
+	for _, name := range []string{s.opts.Path, s.opts.KeyPath} {
+		if name == "" {
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return "", err
+		}
+		stamp += fmt.Sprintf("%d/%d;", info.ModTime().UnixNano(), info.Size())
+	}
+	return stamp, nil
+}
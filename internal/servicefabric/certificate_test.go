@@ -0,0 +1,250 @@
+package servicefabric
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The fixtures in testdata were generated with OpenSSL 3 from one RSA key
+// (client.key) and a leaf certificate (client.pem) issued by ca.pem:
+//
+//	openssl pkcs8 -topk8 -in client.key -v2 aes-256-cbc                        -out client_aes256.key
+//	openssl pkcs8 -topk8 -in client.key -v2 aes-128-cbc -v2prf hmacWithSHA1    -out client_aes128_sha1.key
+//	openssl pkcs8 -topk8 -in client.key -v2 aes-192-cbc -v2prf hmacWithSHA512  -out client_aes192_sha512.key
+//	openssl pkcs8 -topk8 -in client.key -v2 des3                               -out client_des3.key
+//	openssl pkcs8 -topk8 -in client.key -v1 PBE-SHA1-3DES                      -out client_pbes1.key
+//	openssl rsa -in client.key -aes256 -traditional                            -out client_legacy.key
+//	openssl pkcs12 -export -in client.pem -inkey client.key -certfile ca.pem   -out client_aes.pfx
+//	openssl pkcs12 -export -in client.pem -inkey client.key -certfile ca.pem -legacy -out client_legacy.pfx
+//
+// All of them use the password below. other.key is an unrelated P-256 key.
+const testCertificatePassword = "correct-horse"
+
+func testdataPath(name string) string {
+	return filepath.Join("testdata", name)
+}
+
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(testdataPath(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestDecryptPKCS8(t *testing.T) {
+	want := pemBlock(t, readTestdata(t, "client.key")).Bytes
+
+	tests := []struct {
+		name     string
+		file     string
+		password string
+		wantErr  string
+	}{
+		{name: "AES-256-CBC with HMAC-SHA256", file: "client_aes256.key", password: testCertificatePassword},
+		{name: "AES-128-CBC with HMAC-SHA1", file: "client_aes128_sha1.key", password: testCertificatePassword},
+		{name: "AES-192-CBC with HMAC-SHA512", file: "client_aes192_sha512.key", password: testCertificatePassword},
+		{name: "3DES-CBC", file: "client_des3.key", password: testCertificatePassword},
+		{name: "wrong password", file: "client_aes256.key", password: "wrong", wantErr: "incorrect password"},
+		{name: "wrong password with 3DES", file: "client_des3.key", password: "wrong", wantErr: "incorrect password"},
+		{name: "PBES1 is rejected", file: "client_pbes1.key", password: testCertificatePassword, wantErr: "only PBES2 is supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := pemBlock(t, readTestdata(t, tt.file))
+			got, err := decryptPKCS8(block.Bytes, tt.password)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("decryptPKCS8() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decryptPKCS8() returned error: %s", err)
+			}
+			if string(got) != string(want) {
+				t.Fatal("decryptPKCS8() did not return the original PKCS#8 key")
+			}
+		})
+	}
+}
+
+func TestDecryptPKCS8Malformed(t *testing.T) {
+	block := pemBlock(t, readTestdata(t, "client_aes256.key"))
+
+	if _, err := decryptPKCS8([]byte("garbage"), testCertificatePassword); err == nil || !strings.Contains(err.Error(), "parse encrypted private key") {
+		t.Errorf("garbage input: error = %v", err)
+	}
+
+	// Corrupting the final ciphertext block breaks its padding.
+	tampered := append([]byte(nil), block.Bytes...)
+	tampered[len(tampered)-1] ^= 0xff
+	if _, err := decryptPKCS8(tampered, testCertificatePassword); err == nil {
+		t.Error("tampered ciphertext: decryptPKCS8() succeeded")
+	}
+}
+
+func TestParseClientCertificate(t *testing.T) {
+	leafPEM := readTestdata(t, "client.pem")
+	caPEM := readTestdata(t, "ca.pem")
+	chainPEM := append(append([]byte(nil), caPEM...), leafPEM...)
+	wantLeaf := pemBlock(t, leafPEM).Bytes
+	wantCA := pemBlock(t, caPEM).Bytes
+
+	tests := []struct {
+		name      string
+		cert      []byte
+		key       []byte
+		password  string
+		wantChain [][]byte
+		wantErr   string
+	}{
+		{
+			name:      "PEM certificate with separate key",
+			cert:      leafPEM,
+			key:       readTestdata(t, "client.key"),
+			wantChain: [][]byte{wantLeaf},
+		},
+		{
+			name:      "combined PEM puts the leaf first",
+			cert:      append(append([]byte(nil), chainPEM...), readTestdata(t, "client.key")...),
+			wantChain: [][]byte{wantLeaf, wantCA},
+		},
+		{
+			name:      "encrypted PKCS#8 key",
+			cert:      chainPEM,
+			key:       readTestdata(t, "client_aes256.key"),
+			password:  testCertificatePassword,
+			wantChain: [][]byte{wantLeaf, wantCA},
+		},
+		{
+			name:      "3DES encrypted PKCS#8 key",
+			cert:      leafPEM,
+			key:       readTestdata(t, "client_des3.key"),
+			password:  testCertificatePassword,
+			wantChain: [][]byte{wantLeaf},
+		},
+		{
+			name:     "encrypted key with wrong password",
+			cert:     leafPEM,
+			key:      readTestdata(t, "client_aes256.key"),
+			password: "wrong",
+			wantErr:  "incorrect password",
+		},
+		{
+			name:    "encrypted key without password",
+			cert:    leafPEM,
+			key:     readTestdata(t, "client_aes256.key"),
+			wantErr: "no password was provided",
+		},
+		{
+			name:     "legacy encrypted PEM key",
+			cert:     leafPEM,
+			key:      readTestdata(t, "client_legacy.key"),
+			password: testCertificatePassword,
+			wantErr:  "legacy encrypted PEM keys are not supported",
+		},
+		{
+			name:    "key for another certificate",
+			cert:    chainPEM,
+			key:     readTestdata(t, "other.key"),
+			wantErr: "does not match any certificate",
+		},
+		{
+			name:    "certificate without key",
+			cert:    leafPEM,
+			wantErr: "no private key found",
+		},
+		{
+			name:      "PKCS#12 with AES and PBKDF2",
+			cert:      readTestdata(t, "client_aes.pfx"),
+			password:  testCertificatePassword,
+			wantChain: [][]byte{wantLeaf, wantCA},
+		},
+		{
+			name:      "legacy PKCS#12",
+			cert:      readTestdata(t, "client_legacy.pfx"),
+			password:  testCertificatePassword,
+			wantChain: [][]byte{wantLeaf, wantCA},
+		},
+		{
+			name:     "PKCS#12 with wrong password",
+			cert:     readTestdata(t, "client_aes.pfx"),
+			password: "wrong",
+			wantErr:  "pkcs12 decode",
+		},
+		{
+			name:    "PKCS#12 with separate key",
+			cert:    readTestdata(t, "client_aes.pfx"),
+			key:     readTestdata(t, "client.key"),
+			wantErr: "separate private key can only be used with a PEM certificate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert, err := parseClientCertificate(tt.cert, tt.key, tt.password)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseClientCertificate() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseClientCertificate() returned error: %s", err)
+			}
+			assertChain(t, cert, tt.wantChain)
+		})
+	}
+}
+
+func TestLoadClientCertificateInlineContent(t *testing.T) {
+	pfx := readTestdata(t, "client_aes.pfx")
+	leaf := pemBlock(t, readTestdata(t, "client.pem")).Bytes
+	ca := pemBlock(t, readTestdata(t, "ca.pem")).Bytes
+
+	// Base64 copied from a secret store is often wrapped.
+	encoded := base64.StdEncoding.EncodeToString(pfx)
+	wrapped := encoded[:64] + "\n" + encoded[64:]
+
+	cert, err := loadClientCertificate(CertificateOptions{Content: wrapped, Password: testCertificatePassword})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertChain(t, cert, [][]byte{leaf, ca})
+
+	if _, err := loadClientCertificate(CertificateOptions{Path: testdataPath("client_aes.pfx"), Content: wrapped}); err == nil {
+		t.Error("path and content together: loadClientCertificate() succeeded")
+	}
+}
+
+func pemBlock(t *testing.T, data []byte) *pem.Block {
+	t.Helper()
+	block, _ := pem.Decode(data)
+	if block == nil {
+		t.Fatal("no PEM block found")
+	}
+	return block
+}
+
+func assertChain(t *testing.T, cert tls.Certificate, want [][]byte) {
+	t.Helper()
+	if len(cert.Certificate) != len(want) {
+		t.Fatalf("chain has %d certificates, want %d", len(cert.Certificate), len(want))
+	}
+	for i := range want {
+		if string(cert.Certificate[i]) != string(want[i]) {
+			t.Errorf("chain[%d] is not the expected certificate", i)
+		}
+	}
+	if cert.Leaf == nil || string(cert.Leaf.Raw) != string(want[0]) {
+		t.Error("Leaf is not the first certificate of the chain")
+	}
+}
@@ -8,37 +8,50 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const defaultAPIVersion = "6.0"
 
 // Client provides a thin wrapper around the Service Fabric REST API.
 type Client struct {
-	endpoint   *url.URL
+	endpoints  *endpointSet
 	apiVersion string
 	httpClient *http.Client
 	auth       Authenticator
+	cache      *listCache
+	// clusterVersion is the runtime version reported by the cluster once
+	// NegotiateAPIVersion succeeds.
+	clusterVersion string
 }
 
 // ClientConfig configures the Service Fabric client.
 type ClientConfig struct {
-	Endpoint      string
+	Endpoint string
+	// Endpoints lists additional HTTP gateway endpoints of the same cluster,
+	// tried in order after Endpoint when a node is unreachable.
+	Endpoints     []string
 	APIVersion    string
 	HTTPClient    *http.Client
 	Authenticator Authenticator
+	// ListCacheTTL controls how long list responses are shared between
+	// lookups. Zero uses the default of 30 seconds; a negative value disables
+	// caching.
+	ListCacheTTL time.Duration
 }
 
 // NewClient initializes a Service Fabric client.
 func NewClient(cfg ClientConfig) (*Client, error) {
-	if cfg.Endpoint == "" {
-		return nil, fmt.Errorf("endpoint required")
-	}
-	parsed, err := url.Parse(cfg.Endpoint)
+	endpoints, err := newEndpointSet(append([]string{cfg.Endpoint}, cfg.Endpoints...))
 	if err != nil {
-		return nil, fmt.Errorf("invalid endpoint: %w", err)
+		return nil, err
 	}
 	apiVersion := cfg.APIVersion
 	if apiVersion == "" {
@@ -50,19 +63,24 @@ func NewClient(cfg ClientConfig) (*Client, error) {
 			Timeout: 60 * time.Second,
 		}
 	}
+	cacheTTL := cfg.ListCacheTTL
+	if cacheTTL == 0 {
+		cacheTTL = defaultListCacheTTL
+	}
 	return &Client{
-		endpoint:   parsed,
+		endpoints:  endpoints,
 		apiVersion: apiVersion,
 		httpClient: httpClient,
 		auth:       cfg.Authenticator,
+		cache:      newListCache(cacheTTL),
 	}, nil
 }
 
-func (c *Client) buildURL(path string, query url.Values) (string, error) {
+func (c *Client) buildURL(endpoint *url.URL, path string, query url.Values) (string, error) {
 	if !strings.HasPrefix(path, "/") {
 		path = "/" + path
 	}
-	base := *c.endpoint
+	base := *endpoint
 	if base.Path == "" || base.Path == "/" {
 		base.Path = path
 	} else {
@@ -82,39 +100,53 @@ func (c *Client) buildURL(path string, query url.Values) (string, error) {
 }
 
 func (c *Client) doRequest(ctx context.Context, method, path string, query url.Values, body any) (*http.Response, error) {
-	urlStr, err := c.buildURL(path, query)
-	if err != nil {
-		return nil, err
+	if method != http.MethodGet {
+		c.cache.invalidate()
 	}
 
-	var payload io.Reader
+	var payload []byte
 	if body != nil {
 		b, err := json.Marshal(body)
 		if err != nil {
 			return nil, err
 		}
-		payload = bytes.NewReader(b)
+		payload = b
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, urlStr, payload)
-	if err != nil {
-		return nil, err
-	}
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-	req.Header.Set("Accept", "application/json")
-
-	if c.auth != nil {
-		if err := c.auth.Apply(ctx, req); err != nil {
+	resp, err := c.send(ctx, method, func(endpoint *url.URL) (*http.Request, error) {
+		urlStr, err := c.buildURL(endpoint, path, query)
+		if err != nil {
 			return nil, err
 		}
-	}
+		var reader io.Reader
+		if payload != nil {
+			reader = bytes.NewReader(payload)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, urlStr, reader)
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Accept", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+		if c.auth != nil {
+			if err := c.auth.Apply(ctx, req); err != nil {
+				return nil, err
+			}
+		}
+		return req, nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	trace.SpanFromContext(ctx).AddEvent("http.request", trace.WithAttributes(
+		attribute.String("http.request.method", method),
+		attribute.String("url.path", path),
+		attribute.Int("http.response.status_code", resp.StatusCode),
+		attrAPIVersion.String(resp.Request.URL.Query().Get("api-version")),
+	))
 
 	if resp.StatusCode >= 400 {
 		defer resp.Body.Close()
@@ -145,30 +177,38 @@ func (c *Client) doRequest(ctx context.Context, method, path string, query url.V
 	return resp, nil
 }
 
-func (c *Client) pollOperation(ctx context.Context, location string) error {
+func (c *Client) pollOperation(ctx context.Context, location string) (err error) {
 	if location == "" {
 		return nil
 	}
+	ctx, span := startSpan(ctx, "pollOperation", attrOperationID.String(location))
+	defer func() { endSpan(span, err) }()
+
 	// Some locations already include api-version. Respect existing query.
 	var (
-		delay = 5 * time.Second
+		delay    = 5 * time.Second
+		attempts int
 	)
 	for {
-		target, err := c.resolveLocation(location)
-		if err != nil {
-			return err
-		}
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
-		if err != nil {
-			return err
-		}
-		req.Header.Set("Accept", "application/json")
-		if c.auth != nil {
-			if err := c.auth.Apply(ctx, req); err != nil {
-				return err
+		attempts++
+		span.SetAttributes(attrPollAttempts.Int(attempts))
+		resp, err := c.send(ctx, http.MethodGet, func(endpoint *url.URL) (*http.Request, error) {
+			target, err := c.resolveLocation(endpoint, location)
+			if err != nil {
+				return nil, err
 			}
-		}
-		resp, err := c.httpClient.Do(req)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Accept", "application/json")
+			if c.auth != nil {
+				if err := c.auth.Apply(ctx, req); err != nil {
+					return nil, err
+				}
+			}
+			return req, nil
+		})
 		if err != nil {
 			return err
 		}
@@ -186,6 +226,10 @@ func (c *Client) pollOperation(ctx context.Context, location string) error {
 		if err := json.Unmarshal(body, &status); err != nil {
 			return fmt.Errorf("decode operation status: %w: %s", err, string(body))
 		}
+		if status.ID != "" {
+			span.SetAttributes(attrOperationID.String(status.ID))
+		}
+		span.SetAttributes(attrOperationState.String(status.State()))
 
 		switch strings.ToLower(status.State()) {
 		case "succeeded", "success", "completed", "complete":
@@ -208,6 +252,56 @@ func (c *Client) pollOperation(ctx context.Context, location string) error {
 	}
 }
 
+// listPageSize is sent as MaxResults on list endpoints that support it.
+const listPageSize = 500
+
+// listAll fetches every page of a list endpoint by following ContinuationToken.
+// Results are shared through the client's list cache, so callers receive their
+// own copy of the slice.
+func listAll[T any](ctx context.Context, c *Client, path string, query url.Values) ([]T, error) {
+	key := path + "?" + query.Encode()
+	value, err := c.cache.get(ctx, key, func() (any, error) {
+		var (
+			items        []T
+			continuation string
+		)
+		pageQuery := url.Values{}
+		for k, v := range query {
+			pageQuery[k] = v
+		}
+		for {
+			if continuation != "" {
+				pageQuery.Set("ContinuationToken", continuation)
+			} else {
+				pageQuery.Del("ContinuationToken")
+			}
+			resp, err := c.doRequest(ctx, http.MethodGet, path, pageQuery, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			var page pagedList[T]
+			if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+				resp.Body.Close()
+				return nil, err
+			}
+			resp.Body.Close()
+
+			items = append(items, page.Items...)
+			continuation = page.ContinuationToken
+			if continuation == "" {
+				return items, nil
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	items := slices.Clone(value.([]T))
+	trace.SpanFromContext(ctx).SetAttributes(attrResultCount.Int(len(items)))
+	return items, nil
+}
+
 type operationStatus struct {
 	Name   string          `json:"Name"`
 	ID     string          `json:"Id"`
@@ -237,6 +331,12 @@ type operationError struct {
 
 const provisionKindExternalStore = "ExternalStore"
 
+const (
+	applicationTypeStatusAvailable = "Available"
+	applicationTypeStatusFailed    = "Failed"
+	applicationTypeStatusInvalid   = "Invalid"
+)
+
 // provisionApplicationTypeRequest matches Service Fabric JSON ordering requirements.
 type provisionApplicationTypeRequest struct {
 	Kind                          string `json:"Kind"`
@@ -253,7 +353,10 @@ type unprovisionApplicationTypeRequest struct {
 }
 
 // ProvisionApplicationType registers an application type version from an external package.
-func (c *Client) ProvisionApplicationType(ctx context.Context, name, version, packageURI string) error {
+func (c *Client) ProvisionApplicationType(ctx context.Context, name, version, packageURI string) (err error) {
+	ctx, span := startSpan(ctx, "ProvisionApplicationType", attrApplicationTypeName.String(name), attrApplicationTypeVersion.String(version))
+	defer func() { endSpan(span, err) }()
+
 	body := provisionApplicationTypeRequest{
 		Kind:                          provisionKindExternalStore,
 		ApplicationTypeName:           name,
@@ -261,23 +364,35 @@ func (c *Client) ProvisionApplicationType(ctx context.Context, name, version, pa
 		ApplicationPackageDownloadURI: packageURI,
 		Async:                         true,
 	}
-	resp, err := c.doRequest(ctx, http.MethodPost, "/ApplicationTypes/$/Provision", nil, body)
+	resp, err := c.doRequest(ctx, http.MethodPost, "/ApplicationTypes/$/Provision", c.apiVersionQuery(opProvisionApplicationType), body)
 	if err != nil {
+		if IsApplicationTypeAlreadyExistsError(err) {
+			// A previous attempt may still be provisioning the same version.
+			if waitErr := c.waitForApplicationTypeProvisioned(ctx, name, version); waitErr != nil {
+				return waitErr
+			}
+		}
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusAccepted {
-		location := resp.Header.Get("Location")
-		return c.pollOperation(ctx, location)
+		if err := c.pollOperation(ctx, resp.Header.Get("Location")); err != nil {
+			return err
+		}
+	} else {
+		io.Copy(io.Discard, resp.Body)
 	}
-	// For synchronous completion just drain body.
-	io.Copy(io.Discard, resp.Body)
-	return nil
+	// Async provisioning usually completes without a Location header while the
+	// type is still Provisioning, so wait on the type's own status.
+	return c.waitForApplicationTypeProvisioned(ctx, name, version)
 }
 
 // UnprovisionApplicationType removes an application type version from the cluster.
-func (c *Client) UnprovisionApplicationType(ctx context.Context, name, version string, force bool) error {
+func (c *Client) UnprovisionApplicationType(ctx context.Context, name, version string, force bool) (err error) {
+	ctx, span := startSpan(ctx, "UnprovisionApplicationType", attrApplicationTypeName.String(name), attrApplicationTypeVersion.String(version))
+	defer func() { endSpan(span, err) }()
+
 	body := unprovisionApplicationTypeRequest{
 		ApplicationTypeVersion: version,
 		Async:                  true,
@@ -291,15 +406,101 @@ func (c *Client) UnprovisionApplicationType(ctx context.Context, name, version s
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusAccepted {
-		return c.pollOperation(ctx, resp.Header.Get("Location"))
+		if err := c.pollOperation(ctx, resp.Header.Get("Location")); err != nil {
+			return err
+		}
+	} else {
+		io.Copy(io.Discard, resp.Body)
+	}
+	return c.waitForApplicationTypeRemoved(ctx, name, version)
+}
+
+// applicationTypeNotFoundPolls bounds how long a freshly provisioned version may
+// be missing from the listing before provisioning is considered lost.
+const applicationTypeNotFoundPolls = 12
+
+func (c *Client) waitForApplicationTypeProvisioned(ctx context.Context, name, version string) (err error) {
+	ctx, span := startSpan(ctx, "waitForApplicationTypeProvisioned", attrApplicationTypeName.String(name), attrApplicationTypeVersion.String(version))
+	defer func() { endSpan(span, err) }()
+
+	ctx = withoutListCache(ctx)
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	missing := 0
+	for attempts := 1; ; attempts++ {
+		span.SetAttributes(attrPollAttempts.Int(attempts))
+		info, err := c.GetApplicationTypeVersion(ctx, name, version)
+		if err == nil {
+			span.SetAttributes(attrApplicationTypeStatus.String(info.Status))
+		}
+		switch {
+		case IsNotFoundError(err):
+			missing++
+			if missing >= applicationTypeNotFoundPolls {
+				return fmt.Errorf("application type %s/%s did not appear in the cluster after provisioning", name, version)
+			}
+		case err != nil:
+			return err
+		default:
+			switch info.Status {
+			case applicationTypeStatusAvailable:
+				return nil
+			case applicationTypeStatusFailed, applicationTypeStatusInvalid:
+				return fmt.Errorf("provisioning application type %s/%s failed: status=%s details=%s", name, version, info.Status, info.StatusDetails)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Client) waitForApplicationTypeRemoved(ctx context.Context, name, version string) (err error) {
+	ctx, span := startSpan(ctx, "waitForApplicationTypeRemoved", attrApplicationTypeName.String(name), attrApplicationTypeVersion.String(version))
+	defer func() { endSpan(span, err) }()
+
+	ctx = withoutListCache(ctx)
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for attempts := 1; ; attempts++ {
+		span.SetAttributes(attrPollAttempts.Int(attempts))
+		info, err := c.GetApplicationTypeVersion(ctx, name, version)
+		if err == nil {
+			span.SetAttributes(attrApplicationTypeStatus.String(info.Status))
+		}
+		switch {
+		case IsNotFoundError(err):
+			return nil
+		case err != nil:
+			return err
+		case info.Status == applicationTypeStatusFailed:
+			return fmt.Errorf("unprovisioning application type %s/%s failed: details=%s", name, version, info.StatusDetails)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
 	}
-	io.Copy(io.Discard, resp.Body)
-	return nil
 }
 
 // GetApplicationTypeVersion retrieves metadata for a specific application type version.
-func (c *Client) GetApplicationTypeVersion(ctx context.Context, name, version string) (*ApplicationTypeInfo, error) {
-	items, err := c.ListApplicationTypeVersions(ctx, name)
+func (c *Client) GetApplicationTypeVersion(ctx context.Context, name, version string) (_ *ApplicationTypeInfo, err error) {
+	ctx, span := startSpan(ctx, "GetApplicationTypeVersion", attrApplicationTypeName.String(name), attrApplicationTypeVersion.String(version))
+	defer func() { endSpan(span, err) }()
+
+	path := fmt.Sprintf("/ApplicationTypes/%s", url.PathEscape(name))
+	query := url.Values{}
+	query.Set("ApplicationTypeVersion", version)
+	query.Set("ExcludeApplicationParameters", "false")
+	query.Set("MaxResults", strconv.Itoa(listPageSize))
+	items, err := listAll[ApplicationTypeInfo](ctx, c, path, query)
 	if err != nil {
 		return nil, err
 	}
@@ -308,34 +509,39 @@ func (c *Client) GetApplicationTypeVersion(ctx context.Context, name, version st
 			return &item, nil
 		}
 	}
-	return nil, fmt.Errorf("application type %s/%s not found", name, version)
+	return nil, &APIError{
+		Method:     http.MethodGet,
+		Path:       path,
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("application type %s/%s not found", name, version),
+	}
 }
 
 // ListApplicationTypeVersions retrieves application type versions optionally filtered by name.
-func (c *Client) ListApplicationTypeVersions(ctx context.Context, name string) ([]ApplicationTypeInfo, error) {
-	var (
-		path  string
-		query = url.Values{}
-	)
-	query.Set("api-version", "6.0")
+func (c *Client) ListApplicationTypeVersions(ctx context.Context, name string) (_ []ApplicationTypeInfo, err error) {
+	ctx, span := startSpan(ctx, "ListApplicationTypeVersions", attrApplicationTypeName.String(name))
+	defer func() { endSpan(span, err) }()
+
+	path := "/ApplicationTypes"
+	if name != "" {
+		path = fmt.Sprintf("/ApplicationTypes/%s", url.PathEscape(name))
+	}
+	query := url.Values{}
 	query.Set("ExcludeApplicationParameters", "false")
-	path = "/ApplicationTypes"
-	resp, err := c.doRequest(ctx, http.MethodGet, path, query, nil)
+	query.Set("MaxResults", strconv.Itoa(listPageSize))
+	items, err := listAll[ApplicationTypeInfo](ctx, c, path, query)
 	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	var list applicationTypeInfoList
-	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		if name != "" && IsNotFoundError(err) {
+			return nil, nil
+		}
 		return nil, err
 	}
 	if name == "" {
-		return list.Items, nil
+		return items, nil
 	}
 
-	filtered := make([]ApplicationTypeInfo, 0, len(list.Items))
-	for _, item := range list.Items {
+	filtered := make([]ApplicationTypeInfo, 0, len(items))
+	for _, item := range items {
 		if strings.EqualFold(item.TypeName(), name) {
 			filtered = append(filtered, item)
 		}
@@ -344,13 +550,16 @@ func (c *Client) ListApplicationTypeVersions(ctx context.Context, name string) (
 }
 
 // CreateApplication deploys an application using the provided description.
-func (c *Client) CreateApplication(ctx context.Context, app ApplicationDescription) error {
+func (c *Client) CreateApplication(ctx context.Context, app ApplicationDescription) (err error) {
+	ctx, span := startSpan(ctx, "CreateApplication", attrApplicationName.String(app.Name), attrApplicationTypeName.String(app.TypeName), attrApplicationTypeVersion.String(app.TypeVersion))
+	defer func() { endSpan(span, err) }()
+
 	if app.Name == "" {
 		return fmt.Errorf("application name required")
 	}
 	app.prepare()
 	endpoint := "/Applications/$/Create"
-	resp, err := c.doRequest(ctx, http.MethodPost, endpoint, nil, app)
+	resp, err := c.doRequest(ctx, http.MethodPost, endpoint, c.apiVersionQuery(opCreateApplication), app)
 	if err != nil {
 		return err
 	}
@@ -363,8 +572,12 @@ func (c *Client) CreateApplication(ctx context.Context, app ApplicationDescripti
 	return nil
 }
 
-// DeleteApplication removes an application.
-func (c *Client) DeleteApplication(ctx context.Context, name string, force bool) error {
+// DeleteApplication removes an application and waits until it can no longer
+// be read, so an application of the same name can be created right away.
+func (c *Client) DeleteApplication(ctx context.Context, name string, force bool) (err error) {
+	ctx, span := startSpan(ctx, "DeleteApplication", attrApplicationName.String(name))
+	defer func() { endSpan(span, err) }()
+
 	appID := url.PathEscape(applicationIDFromName(name))
 	endpoint := fmt.Sprintf("/Applications/%s/$/Delete", appID)
 	query := url.Values{}
@@ -378,62 +591,136 @@ func (c *Client) DeleteApplication(ctx context.Context, name string, force bool)
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusAccepted {
-		return c.pollOperation(ctx, resp.Header.Get("Location"))
+		if err := c.pollOperation(ctx, resp.Header.Get("Location")); err != nil {
+			return err
+		}
+	} else {
+		io.Copy(io.Discard, resp.Body)
+	}
+	return c.waitForApplicationDeleted(ctx, name)
+}
+
+func (c *Client) waitForApplicationDeleted(ctx context.Context, name string) (err error) {
+	ctx, span := startSpan(ctx, "waitForApplicationDeleted", attrApplicationName.String(name))
+	defer func() { endSpan(span, err) }()
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for attempts := 1; ; attempts++ {
+		span.SetAttributes(attrPollAttempts.Int(attempts))
+		_, err := c.GetApplication(ctx, name)
+		switch {
+		case IsNotFoundError(err):
+			return nil
+		case err != nil:
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
 	}
-	io.Copy(io.Discard, resp.Body)
-	return nil
 }
 
 const (
-	upgradeKindRolling              = "Rolling"
-	rollingUpgradeModeUnmonitored   = "UnmonitoredAuto"
-	upgradeStateRollingForwardDone  = "RollingForwardCompleted"
-	upgradeStateRollingBackDone     = "RollingBackCompleted"
-	upgradeStateRollingBackProgress = "RollingBackInProgress"
-	upgradeStateFailed              = "Failed"
+	upgradeKindRolling                 = "Rolling"
+	rollingUpgradeModeUnmonitored      = "UnmonitoredAuto"
+	rollingUpgradeModeManual           = "UnmonitoredManual"
+	upgradeStateRollingForwardDone     = "RollingForwardCompleted"
+	upgradeStateRollingBackDone        = "RollingBackCompleted"
+	upgradeStateRollingBackProgress    = "RollingBackInProgress"
+	upgradeStateRollingForwardPending  = "RollingForwardPending"
+	upgradeStateRollingForwardProgress = "RollingForwardInProgress"
+	upgradeStateFailed                 = "Failed"
 )
 
 // ApplicationUpgradeDescription describes an application upgrade request.
 type ApplicationUpgradeDescription struct {
-	Name                         string                          `json:"Name"`
-	TargetApplicationTypeVersion string                          `json:"TargetApplicationTypeVersion"`
-	ParameterMap                 map[string]string               `json:"-"`
-	Parameters                   []NameValueParameter            `json:"Parameters,omitempty"`
-	UpgradeKind                  string                          `json:"UpgradeKind"`
-	RollingUpgradeMode           string                          `json:"RollingUpgradeMode,omitempty"`
-	ForceRestart                 bool                            `json:"ForceRestart,omitempty"`
-	ApplicationHealthPolicy      *ApplicationHealthPolicy        `json:"ApplicationHealthPolicy,omitempty"`
-	MonitoringPolicy             *RollingUpgradeMonitoringPolicy `json:"MonitoringPolicy,omitempty"`
+	Name                                   string                                 `json:"Name"`
+	TargetApplicationTypeVersion           string                                 `json:"TargetApplicationTypeVersion"`
+	ParameterMap                           map[string]string                      `json:"-"`
+	Parameters                             []NameValueParameter                   `json:"Parameters,omitempty"`
+	UpgradeKind                            string                                 `json:"UpgradeKind"`
+	RollingUpgradeMode                     string                                 `json:"RollingUpgradeMode,omitempty"`
+	UpgradeReplicaSetCheckTimeoutInSeconds *int64                                 `json:"UpgradeReplicaSetCheckTimeoutInSeconds,omitempty"`
+	ForceRestart                           bool                                   `json:"ForceRestart,omitempty"`
+	SortOrder                              string                                 `json:"SortOrder,omitempty"`
+	InstanceCloseDelayDurationInSeconds    *int64                                 `json:"InstanceCloseDelayDurationInSeconds,omitempty"`
+	RecreateApplication                    bool                                   `json:"RecreateApplication,omitempty"`
+	ManagedApplicationIdentity             *ManagedApplicationIdentityDescription `json:"ManagedApplicationIdentity,omitempty"`
+	ApplicationHealthPolicy                *ApplicationHealthPolicy               `json:"ApplicationHealthPolicy,omitempty"`
+	MonitoringPolicy                       *RollingUpgradeMonitoringPolicy        `json:"MonitoringPolicy,omitempty"`
 }
 
 func (d *ApplicationUpgradeDescription) prepare() {
 	if len(d.Parameters) == 0 && len(d.ParameterMap) > 0 {
 		d.Parameters = mapToParameterList(d.ParameterMap)
 	}
+	if d.UpgradeKind == "" {
+		d.UpgradeKind = upgradeKindRolling
+	}
+	if d.RollingUpgradeMode == "" && !d.RecreateApplication {
+		d.RollingUpgradeMode = rollingUpgradeModeUnmonitored
+	}
+}
+
+// ApplicationUpgradeProgress reports the state of the most recent upgrade of
+// an application.
+type ApplicationUpgradeProgress struct {
+	TargetApplicationTypeVersion string              `json:"TargetApplicationTypeVersion"`
+	UpgradeState                 string              `json:"UpgradeState"`
+	RollingUpgradeMode           string              `json:"RollingUpgradeMode"`
+	NextUpgradeDomain            string              `json:"NextUpgradeDomain"`
+	UpgradeDomains               []UpgradeDomainInfo `json:"UpgradeDomains"`
+	FailureReason                string              `json:"FailureReason"`
+	UpgradeStatusDetails         string              `json:"UpgradeStatusDetails"`
+	// UpgradeDescription is the request the upgrade was started with.
+	UpgradeDescription *ApplicationUpgradeDescription `json:"UpgradeDescription"`
+}
+
+// awaitingManualStep reports whether the upgrade is paused until the next
+// upgrade domain is started by hand. In UnmonitoredManual mode this is the
+// only way RollingForwardPending ends, so waiting for it never finishes.
+func (p *ApplicationUpgradeProgress) awaitingManualStep() bool {
+	return p.UpgradeState == upgradeStateRollingForwardPending && p.RollingUpgradeMode == rollingUpgradeModeManual
 }
 
-type applicationUpgradeProgress struct {
-	UpgradeState         string `json:"UpgradeState"`
-	FailureReason        string `json:"FailureReason"`
-	UpgradeStatusDetails string `json:"UpgradeStatusDetails"`
+func manualUpgradeStepError(name string, progress *ApplicationUpgradeProgress) error {
+	return fmt.Errorf("upgrade of application %s is waiting in UnmonitoredManual mode for upgrade domain %q to be started; resume it with sfctl application upgrade-resume or Resume-ServiceFabricApplicationUpgrade, then apply again", name, progress.NextUpgradeDomain)
+}
+
+// UpgradeDomainInfo is the upgrade state of a single upgrade domain.
+type UpgradeDomainInfo struct {
+	Name  string `json:"Name"`
+	State string `json:"State"`
+}
+
+// IsUpgradeInProgress reports whether an application upgrade in the given
+// state is still rolling forward or back.
+func IsUpgradeInProgress(upgradeState string) bool {
+	switch upgradeState {
+	case upgradeStateRollingForwardPending, upgradeStateRollingForwardProgress, upgradeStateRollingBackProgress:
+		return true
+	}
+	return false
 }
 
 // UpgradeApplication triggers a rolling upgrade and waits for completion.
-func (c *Client) UpgradeApplication(ctx context.Context, desc ApplicationUpgradeDescription) error {
+func (c *Client) UpgradeApplication(ctx context.Context, desc ApplicationUpgradeDescription) (err error) {
+	ctx, span := startSpan(ctx, "UpgradeApplication", attrApplicationName.String(desc.Name), attrApplicationTypeVersion.String(desc.TargetApplicationTypeVersion))
+	defer func() { endSpan(span, err) }()
+
 	if desc.Name == "" {
 		return fmt.Errorf("application name required")
 	}
 	desc.prepare()
-	if desc.UpgradeKind == "" {
-		desc.UpgradeKind = upgradeKindRolling
-	}
-	if desc.RollingUpgradeMode == "" {
-		desc.RollingUpgradeMode = rollingUpgradeModeUnmonitored
-	}
 
 	if err := c.startApplicationUpgrade(ctx, desc); err != nil {
 		if IsApplicationUpgradeInProgressError(err) {
-			if waitErr := c.waitForApplicationUpgrade(ctx, desc.Name); waitErr != nil {
+			if waitErr := c.WaitForApplicationUpgrade(ctx, desc.Name); waitErr != nil {
 				return waitErr
 			}
 			if err := c.startApplicationUpgrade(ctx, desc); err != nil {
@@ -443,13 +730,26 @@ func (c *Client) UpgradeApplication(ctx context.Context, desc ApplicationUpgrade
 			return err
 		}
 	}
-	return c.waitForApplicationUpgrade(ctx, desc.Name)
+	return c.WaitForApplicationUpgrade(ctx, desc.Name)
+}
+
+// StartApplicationUpgrade starts a rolling upgrade and returns without waiting
+// for it to complete. Use GetApplicationUpgradeProgress to follow it.
+func (c *Client) StartApplicationUpgrade(ctx context.Context, desc ApplicationUpgradeDescription) (err error) {
+	ctx, span := startSpan(ctx, "StartApplicationUpgrade", attrApplicationName.String(desc.Name), attrApplicationTypeVersion.String(desc.TargetApplicationTypeVersion))
+	defer func() { endSpan(span, err) }()
+
+	if desc.Name == "" {
+		return fmt.Errorf("application name required")
+	}
+	desc.prepare()
+	return c.startApplicationUpgrade(ctx, desc)
 }
 
 func (c *Client) startApplicationUpgrade(ctx context.Context, desc ApplicationUpgradeDescription) error {
 	appID := url.PathEscape(applicationIDFromName(desc.Name))
 	endpoint := fmt.Sprintf("/Applications/%s/$/Upgrade", appID)
-	resp, err := c.doRequest(ctx, http.MethodPost, endpoint, nil, desc)
+	resp, err := c.doRequest(ctx, http.MethodPost, endpoint, c.apiVersionQuery(opUpgradeApplication), desc)
 	if err != nil {
 		return err
 	}
@@ -458,11 +758,18 @@ func (c *Client) startApplicationUpgrade(ctx context.Context, desc ApplicationUp
 	return nil
 }
 
-func (c *Client) waitForApplicationUpgrade(ctx context.Context, name string) error {
+// WaitForApplicationUpgrade blocks until the current upgrade of the application
+// completes, returning an error if it rolled back or failed, or if it is
+// waiting for a manual step in UnmonitoredManual mode.
+func (c *Client) WaitForApplicationUpgrade(ctx context.Context, name string) (err error) {
+	ctx, span := startSpan(ctx, "WaitForApplicationUpgrade", attrApplicationName.String(name))
+	defer func() { endSpan(span, err) }()
+
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
-	for {
+	for attempts := 1; ; attempts++ {
+		span.SetAttributes(attrPollAttempts.Int(attempts))
 		progress, err := c.getApplicationUpgradeProgress(ctx, name)
 		if err != nil {
 			if IsNotFoundError(err) {
@@ -470,13 +777,17 @@ func (c *Client) waitForApplicationUpgrade(ctx context.Context, name string) err
 			}
 			return err
 		}
+		span.SetAttributes(attrUpgradeState.String(progress.UpgradeState))
+		if progress.awaitingManualStep() {
+			return manualUpgradeStepError(name, progress)
+		}
 
 		switch progress.UpgradeState {
 		case upgradeStateRollingForwardDone, "":
 			return nil
 		case upgradeStateRollingBackDone, upgradeStateFailed:
 			return fmt.Errorf("application upgrade failed: state=%s details=%s", progress.UpgradeState, progress.UpgradeStatusDetails)
-		case upgradeStateRollingBackProgress, "RollingForwardPending", "RollingForwardInProgress", "Invalid":
+		case upgradeStateRollingBackProgress, upgradeStateRollingForwardPending, upgradeStateRollingForwardProgress, "Invalid":
 			// continue polling
 		default:
 			// Unknown state, continue polling but guard against hangs.
@@ -490,16 +801,62 @@ func (c *Client) waitForApplicationUpgrade(ctx context.Context, name string) err
 	}
 }
 
-func (c *Client) getApplicationUpgradeProgress(ctx context.Context, name string) (*applicationUpgradeProgress, error) {
+// WaitForApplicationUpgradeIdle blocks until the application has no upgrade in
+// progress. Unlike UpgradeApplication it does not treat a rolled back or failed
+// upgrade as an error: callers only need the application to accept changes
+// again. An upgrade waiting for a manual step is an error, since it only
+// becomes idle once someone resumes it.
+func (c *Client) WaitForApplicationUpgradeIdle(ctx context.Context, name string) (err error) {
+	ctx, span := startSpan(ctx, "WaitForApplicationUpgradeIdle", attrApplicationName.String(name))
+	defer func() { endSpan(span, err) }()
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for attempts := 1; ; attempts++ {
+		span.SetAttributes(attrPollAttempts.Int(attempts))
+		progress, err := c.getApplicationUpgradeProgress(ctx, name)
+		if err != nil {
+			if IsNotFoundError(err) {
+				return nil
+			}
+			return err
+		}
+		span.SetAttributes(attrUpgradeState.String(progress.UpgradeState))
+		if !IsUpgradeInProgress(progress.UpgradeState) {
+			return nil
+		}
+		if progress.awaitingManualStep() {
+			return manualUpgradeStepError(name, progress)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetApplicationUpgradeProgress returns the progress of the most recent upgrade
+// of the application.
+func (c *Client) GetApplicationUpgradeProgress(ctx context.Context, name string) (_ *ApplicationUpgradeProgress, err error) {
+	ctx, span := startSpan(ctx, "GetApplicationUpgradeProgress", attrApplicationName.String(name))
+	defer func() { endSpan(span, err) }()
+
+	return c.getApplicationUpgradeProgress(ctx, name)
+}
+
+func (c *Client) getApplicationUpgradeProgress(ctx context.Context, name string) (*ApplicationUpgradeProgress, error) {
 	appID := url.PathEscape(applicationIDFromName(name))
 	endpoint := fmt.Sprintf("/Applications/%s/$/GetUpgradeProgress", appID)
-	resp, err := c.doRequest(ctx, http.MethodGet, endpoint, nil, nil)
+	resp, err := c.doRequest(ctx, http.MethodGet, endpoint, c.apiVersionQuery(opGetUpgradeProgress), nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var progress applicationUpgradeProgress
+	var progress ApplicationUpgradeProgress
 	if err := json.NewDecoder(resp.Body).Decode(&progress); err != nil {
 		return nil, err
 	}
@@ -507,10 +864,13 @@ func (c *Client) getApplicationUpgradeProgress(ctx context.Context, name string)
 }
 
 // GetApplication retrieves application information.
-func (c *Client) GetApplication(ctx context.Context, name string) (*ApplicationInfo, error) {
+func (c *Client) GetApplication(ctx context.Context, name string) (_ *ApplicationInfo, err error) {
+	ctx, span := startSpan(ctx, "GetApplication", attrApplicationName.String(name))
+	defer func() { endSpan(span, err) }()
+
 	appID := url.PathEscape(applicationIDFromName(name))
 	endpoint := fmt.Sprintf("/Applications/%s", appID)
-	resp, err := c.doRequest(ctx, http.MethodGet, endpoint, nil, nil)
+	resp, err := c.doRequest(ctx, http.MethodGet, endpoint, c.apiVersionQuery(opGetApplication), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -562,26 +922,24 @@ func (c *Client) GetApplication(ctx context.Context, name string) (*ApplicationI
 }
 
 // ListApplications returns all applications optionally filtered by type name.
-func (c *Client) ListApplications(ctx context.Context, typeName string) ([]ApplicationInfo, error) {
+func (c *Client) ListApplications(ctx context.Context, typeName string) (_ []ApplicationInfo, err error) {
+	ctx, span := startSpan(ctx, "ListApplications", attrApplicationTypeName.String(typeName))
+	defer func() { endSpan(span, err) }()
+
 	query := url.Values{}
 	if typeName != "" {
 		query.Set("ApplicationTypeName", typeName)
 	}
-	resp, err := c.doRequest(ctx, http.MethodGet, "/Applications/$/GetApplications", query, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	var list applicationInfoList
-	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
-		return nil, err
-	}
-	return list.Items, nil
+	query.Set("MaxResults", strconv.Itoa(listPageSize))
+	query.Set("api-version", c.apiVersionFor(opGetApplications))
+	return listAll[ApplicationInfo](ctx, c, "/Applications/$/GetApplications", query)
 }
 
 // CreateService schedules creation of a stateful or stateless service within an application.
-func (c *Client) CreateService(ctx context.Context, desc any) error {
+func (c *Client) CreateService(ctx context.Context, desc any) (err error) {
+	ctx, span := startSpan(ctx, "CreateService")
+	defer func() { endSpan(span, err) }()
+
 	base := serviceDescriptionBase(desc)
 	if base == nil {
 		return fmt.Errorf("unsupported service description type %T", desc)
@@ -598,10 +956,11 @@ func (c *Client) CreateService(ctx context.Context, desc any) error {
 	if base.PartitionDescription.PartitionScheme == "" {
 		return fmt.Errorf("partition scheme required")
 	}
+	span.SetAttributes(attrApplicationName.String(base.ApplicationName), attrServiceName.String(base.ServiceName), attrServiceTypeName.String(base.ServiceTypeName))
 
 	appID := url.PathEscape(applicationIDFromName(base.ApplicationName))
 	path := fmt.Sprintf("/Applications/%s/$/GetServices/$/Create", appID)
-	resp, err := c.doRequest(ctx, http.MethodPost, path, nil, desc)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, c.apiVersionQuery(opCreateService), desc)
 	if err != nil {
 		return err
 	}
@@ -615,13 +974,16 @@ func (c *Client) CreateService(ctx context.Context, desc any) error {
 }
 
 // UpdateService modifies mutable properties of an existing service.
-func (c *Client) UpdateService(ctx context.Context, serviceName string, desc any) error {
+func (c *Client) UpdateService(ctx context.Context, serviceName string, desc any) (err error) {
+	ctx, span := startSpan(ctx, "UpdateService", attrServiceName.String(serviceName))
+	defer func() { endSpan(span, err) }()
+
 	if serviceName == "" {
 		return fmt.Errorf("service name required")
 	}
 	serviceID := url.PathEscape(serviceIDFromName(serviceName))
 	path := fmt.Sprintf("/Services/%s/$/Update", serviceID)
-	resp, err := c.doRequest(ctx, http.MethodPost, path, nil, desc)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, c.apiVersionQuery(opUpdateService), desc)
 	if err != nil {
 		return err
 	}
@@ -634,10 +996,14 @@ func (c *Client) UpdateService(ctx context.Context, serviceName string, desc any
 	return nil
 }
 
-// DeleteService removes a Service Fabric service.
-func (c *Client) DeleteService(ctx context.Context, serviceName string, force bool) error {
-	if serviceName == "" {
-		return fmt.Errorf("service name required")
+// DeleteService removes a Service Fabric service and waits until it can no
+// longer be read.
+func (c *Client) DeleteService(ctx context.Context, applicationName, serviceName string, force bool) (err error) {
+	ctx, span := startSpan(ctx, "DeleteService", attrApplicationName.String(applicationName), attrServiceName.String(serviceName))
+	defer func() { endSpan(span, err) }()
+
+	if applicationName == "" || serviceName == "" {
+		return fmt.Errorf("application and service names are required")
 	}
 	serviceID := url.PathEscape(serviceIDFromName(serviceName))
 	path := fmt.Sprintf("/Services/%s/$/Delete", serviceID)
@@ -652,14 +1018,45 @@ func (c *Client) DeleteService(ctx context.Context, serviceName string, force bo
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusAccepted {
-		return c.pollOperation(ctx, resp.Header.Get("Location"))
+		if err := c.pollOperation(ctx, resp.Header.Get("Location")); err != nil {
+			return err
+		}
+	} else {
+		io.Copy(io.Discard, resp.Body)
+	}
+	return c.waitForServiceDeleted(ctx, applicationName, serviceName)
+}
+
+func (c *Client) waitForServiceDeleted(ctx context.Context, applicationName, serviceName string) (err error) {
+	ctx, span := startSpan(ctx, "waitForServiceDeleted", attrApplicationName.String(applicationName), attrServiceName.String(serviceName))
+	defer func() { endSpan(span, err) }()
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for attempts := 1; ; attempts++ {
+		span.SetAttributes(attrPollAttempts.Int(attempts))
+		_, err := c.GetService(ctx, applicationName, serviceName)
+		switch {
+		case IsNotFoundError(err):
+			return nil
+		case err != nil:
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
 	}
-	io.Copy(io.Discard, resp.Body)
-	return nil
 }
 
 // ListServiceTypes returns service types declared in an application type version.
-func (c *Client) ListServiceTypes(ctx context.Context, applicationTypeName, applicationTypeVersion string) ([]ServiceTypeInfo, error) {
+func (c *Client) ListServiceTypes(ctx context.Context, applicationTypeName, applicationTypeVersion string) (_ []ServiceTypeInfo, err error) {
+	ctx, span := startSpan(ctx, "ListServiceTypes", attrApplicationTypeName.String(applicationTypeName), attrApplicationTypeVersion.String(applicationTypeVersion))
+	defer func() { endSpan(span, err) }()
+
 	if applicationTypeName == "" {
 		return nil, fmt.Errorf("application type name required")
 	}
@@ -683,7 +1080,10 @@ func (c *Client) ListServiceTypes(ctx context.Context, applicationTypeName, appl
 }
 
 // GetServiceType retrieves metadata for a specific service type within an application type version.
-func (c *Client) GetServiceType(ctx context.Context, applicationTypeName, applicationTypeVersion, serviceTypeName string) (*ServiceTypeInfo, error) {
+func (c *Client) GetServiceType(ctx context.Context, applicationTypeName, applicationTypeVersion, serviceTypeName string) (_ *ServiceTypeInfo, err error) {
+	ctx, span := startSpan(ctx, "GetServiceType", attrApplicationTypeName.String(applicationTypeName), attrApplicationTypeVersion.String(applicationTypeVersion), attrServiceTypeName.String(serviceTypeName))
+	defer func() { endSpan(span, err) }()
+
 	if applicationTypeName == "" || applicationTypeVersion == "" || serviceTypeName == "" {
 		return nil, fmt.Errorf("application type name, version, and service type name are required")
 	}
@@ -714,7 +1114,10 @@ func (c *Client) GetServiceType(ctx context.Context, applicationTypeName, applic
 }
 
 // GetService retrieves information about a Service Fabric service within an application.
-func (c *Client) GetService(ctx context.Context, applicationName, serviceName string) (*ServiceInfo, error) {
+func (c *Client) GetService(ctx context.Context, applicationName, serviceName string) (_ *ServiceInfo, err error) {
+	ctx, span := startSpan(ctx, "GetService", attrApplicationName.String(applicationName), attrServiceName.String(serviceName))
+	defer func() { endSpan(span, err) }()
+
 	if applicationName == "" || serviceName == "" {
 		return nil, fmt.Errorf("application and service names are required")
 	}
@@ -744,8 +1147,44 @@ func (c *Client) GetService(ctx context.Context, applicationName, serviceName st
 	return &info, nil
 }
 
+// GetServiceDescription retrieves the description a service was created with.
+func (c *Client) GetServiceDescription(ctx context.Context, serviceName string) (_ *ServiceDescriptionInfo, err error) {
+	ctx, span := startSpan(ctx, "GetServiceDescription", attrServiceName.String(serviceName))
+	defer func() { endSpan(span, err) }()
+
+	if serviceName == "" {
+		return nil, fmt.Errorf("service name required")
+	}
+	serviceID := url.PathEscape(serviceIDFromName(serviceName))
+	path := fmt.Sprintf("/Services/%s/$/GetDescription", serviceID)
+	resp, err := c.doRequest(ctx, http.MethodGet, path, c.apiVersionQuery(opGetServiceDescription), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		io.Copy(io.Discard, resp.Body)
+		return nil, &APIError{
+			Method:     http.MethodGet,
+			Path:       path,
+			StatusCode: http.StatusNotFound,
+			Message:    "service not found",
+		}
+	}
+
+	var info ServiceDescriptionInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
 // ListServices retrieves services within an application optionally filtered by service type.
-func (c *Client) ListServices(ctx context.Context, applicationName, serviceTypeName string) ([]ServiceInfo, error) {
+func (c *Client) ListServices(ctx context.Context, applicationName, serviceTypeName string) (_ []ServiceInfo, err error) {
+	ctx, span := startSpan(ctx, "ListServices", attrApplicationName.String(applicationName), attrServiceTypeName.String(serviceTypeName))
+	defer func() { endSpan(span, err) }()
+
 	if applicationName == "" {
 		return nil, fmt.Errorf("application name required")
 	}
@@ -755,50 +1194,155 @@ func (c *Client) ListServices(ctx context.Context, applicationName, serviceTypeN
 	if serviceTypeName != "" {
 		query.Set("ServiceTypeName", serviceTypeName)
 	}
+	return listAll[ServiceInfo](ctx, c, path, query)
+}
 
-	var (
-		items        []ServiceInfo
-		continuation string
-	)
-	for {
-		if continuation != "" {
-			query.Set("ContinuationToken", continuation)
-		} else {
-			query.Del("ContinuationToken")
-		}
-		resp, err := c.doRequest(ctx, http.MethodGet, path, query, nil)
-		if err != nil {
-			return nil, err
-		}
+// CreateServiceGroup creates a service group and its member services within an
+// application.
+func (c *Client) CreateServiceGroup(ctx context.Context, desc ServiceGroupDescription) (err error) {
+	ctx, span := startSpan(ctx, "CreateServiceGroup", attrApplicationName.String(desc.ApplicationName), attrServiceName.String(desc.ServiceName), attrServiceTypeName.String(desc.ServiceTypeName))
+	defer func() { endSpan(span, err) }()
 
-		var page pagedServiceInfoList
-		if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
-			resp.Body.Close()
-			return nil, err
+	if desc.ApplicationName == "" {
+		return fmt.Errorf("application name required")
+	}
+	if desc.ServiceName == "" {
+		return fmt.Errorf("service group name required")
+	}
+	if desc.ServiceTypeName == "" {
+		return fmt.Errorf("service group type name required")
+	}
+	if desc.PartitionDescription.PartitionScheme == "" {
+		return fmt.Errorf("partition scheme required")
+	}
+	if len(desc.ServiceGroupMemberDescription) == 0 {
+		return fmt.Errorf("at least one service group member required")
+	}
+
+	appID := url.PathEscape(applicationIDFromName(desc.ApplicationName))
+	path := fmt.Sprintf("/Applications/%s/$/GetServiceGroups/$/Create", appID)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, c.apiVersionQuery(opCreateServiceGroup), desc)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusAccepted {
+		return c.pollOperation(ctx, resp.Header.Get("Location"))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// UpdateServiceGroup modifies mutable properties of an existing service group.
+func (c *Client) UpdateServiceGroup(ctx context.Context, serviceGroupName string, desc ServiceGroupUpdateDescription) (err error) {
+	ctx, span := startSpan(ctx, "UpdateServiceGroup", attrServiceName.String(serviceGroupName))
+	defer func() { endSpan(span, err) }()
+
+	if serviceGroupName == "" {
+		return fmt.Errorf("service group name required")
+	}
+	serviceID := url.PathEscape(serviceIDFromName(serviceGroupName))
+	path := fmt.Sprintf("/Services/%s/$/UpdateServiceGroup", serviceID)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, c.apiVersionQuery(opUpdateServiceGroup), desc)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusAccepted {
+		return c.pollOperation(ctx, resp.Header.Get("Location"))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// DeleteServiceGroup removes a service group with its members and waits until
+// it can no longer be read.
+func (c *Client) DeleteServiceGroup(ctx context.Context, applicationName, serviceGroupName string) (err error) {
+	ctx, span := startSpan(ctx, "DeleteServiceGroup", attrApplicationName.String(applicationName), attrServiceName.String(serviceGroupName))
+	defer func() { endSpan(span, err) }()
+
+	if applicationName == "" || serviceGroupName == "" {
+		return fmt.Errorf("application and service group names are required")
+	}
+	serviceID := url.PathEscape(serviceIDFromName(serviceGroupName))
+	path := fmt.Sprintf("/Services/%s/$/DeleteServiceGroup", serviceID)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, c.apiVersionQuery(opDeleteServiceGroup), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusAccepted {
+		if err := c.pollOperation(ctx, resp.Header.Get("Location")); err != nil {
+			return err
 		}
-		resp.Body.Close()
+	} else {
+		io.Copy(io.Discard, resp.Body)
+	}
+	return c.waitForServiceDeleted(ctx, applicationName, serviceGroupName)
+}
+
+// GetServiceGroupDescription retrieves the description a service group was
+// created with.
+func (c *Client) GetServiceGroupDescription(ctx context.Context, serviceGroupName string) (_ *ServiceGroupDescriptionInfo, err error) {
+	ctx, span := startSpan(ctx, "GetServiceGroupDescription", attrServiceName.String(serviceGroupName))
+	defer func() { endSpan(span, err) }()
+
+	if serviceGroupName == "" {
+		return nil, fmt.Errorf("service group name required")
+	}
+	serviceID := url.PathEscape(serviceIDFromName(serviceGroupName))
+	path := fmt.Sprintf("/Services/%s/$/GetServiceGroupDescription", serviceID)
+	resp, err := c.doRequest(ctx, http.MethodGet, path, c.apiVersionQuery(opGetServiceGroupDesc), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-		items = append(items, page.Items...)
-		continuation = page.ContinuationToken
-		if continuation == "" {
-			break
+	if resp.StatusCode == http.StatusNoContent {
+		io.Copy(io.Discard, resp.Body)
+		return nil, &APIError{
+			Method:     http.MethodGet,
+			Path:       path,
+			StatusCode: http.StatusNotFound,
+			Message:    "service group not found",
 		}
 	}
-	return items, nil
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	// Clusters wrap the description in a ServiceGroupDescription property;
+	// accept the flat shape as well.
+	var envelope struct {
+		ServiceGroupDescription json.RawMessage `json:"ServiceGroupDescription"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, err
+	}
+	if len(envelope.ServiceGroupDescription) > 0 && string(envelope.ServiceGroupDescription) != "null" {
+		body = envelope.ServiceGroupDescription
+	}
+	var info ServiceGroupDescriptionInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
 }
 
 // ApplicationTypeInfo describes an application type version registered in the cluster.
 type ApplicationTypeInfo struct {
-	Name                   string               `json:"Name"`
-	Version                string               `json:"Version"`
-	ApplicationTypeName    string               `json:"ApplicationTypeName"`
-	ApplicationTypeVersion string               `json:"ApplicationTypeVersion"`
-	Status                 string               `json:"Status"`
-	DefaultParameterList   []NameValueParameter `json:"DefaultParameterList"`
-}
-
-type applicationTypeInfoList struct {
-	Items []ApplicationTypeInfo `json:"Items"`
+	Name                          string               `json:"Name"`
+	Version                       string               `json:"Version"`
+	ApplicationTypeName           string               `json:"ApplicationTypeName"`
+	ApplicationTypeVersion        string               `json:"ApplicationTypeVersion"`
+	Status                        string               `json:"Status"`
+	StatusDetails                 string               `json:"StatusDetails"`
+	ApplicationTypeDefinitionKind string               `json:"ApplicationTypeDefinitionKind"`
+	DefaultParameterList          []NameValueParameter `json:"DefaultParameterList"`
 }
 
 func (a ApplicationTypeInfo) TypeName() string {
@@ -873,8 +1417,23 @@ type ApplicationInfo struct {
 
 // ApplicationHealthPolicy controls health thresholds during upgrades.
 type ApplicationHealthPolicy struct {
-	ConsiderWarningAsError                  bool   `json:"ConsiderWarningAsError,omitempty"`
-	MaxPercentUnhealthyDeployedApplications *int64 `json:"MaxPercentUnhealthyDeployedApplications,omitempty"`
+	ConsiderWarningAsError                  bool                             `json:"ConsiderWarningAsError,omitempty"`
+	MaxPercentUnhealthyDeployedApplications *int64                           `json:"MaxPercentUnhealthyDeployedApplications,omitempty"`
+	DefaultServiceTypeHealthPolicy          *ServiceTypeHealthPolicy         `json:"DefaultServiceTypeHealthPolicy,omitempty"`
+	ServiceTypeHealthPolicyMap              []ServiceTypeHealthPolicyMapItem `json:"ServiceTypeHealthPolicyMap,omitempty"`
+}
+
+// ServiceTypeHealthPolicy sets the unhealthy thresholds for services of a type.
+type ServiceTypeHealthPolicy struct {
+	MaxPercentUnhealthyPartitionsPerService *int64 `json:"MaxPercentUnhealthyPartitionsPerService,omitempty"`
+	MaxPercentUnhealthyReplicasPerPartition *int64 `json:"MaxPercentUnhealthyReplicasPerPartition,omitempty"`
+	MaxPercentUnhealthyServices             *int64 `json:"MaxPercentUnhealthyServices,omitempty"`
+}
+
+// ServiceTypeHealthPolicyMapItem applies a health policy to one service type.
+type ServiceTypeHealthPolicyMapItem struct {
+	Key   string                  `json:"Key"`
+	Value ServiceTypeHealthPolicy `json:"Value"`
 }
 
 // RollingUpgradeMonitoringPolicy adjusts how Service Fabric monitors upgrades.
@@ -887,10 +1446,6 @@ type RollingUpgradeMonitoringPolicy struct {
 	UpgradeDomainTimeoutInMilliseconds      string `json:"UpgradeDomainTimeoutInMilliseconds,omitempty"`
 }
 
-type applicationInfoList struct {
-	Items []ApplicationInfo `json:"Items"`
-}
-
 // PartitionDescription describes a service partitioning scheme.
 type PartitionDescription struct {
 	PartitionScheme string   `json:"PartitionScheme"`
@@ -964,6 +1519,143 @@ type StatefulServiceUpdateDescription struct {
 	ServicePlacementTimeLimitSeconds  *string `json:"ServicePlacementTimeLimitSeconds,omitempty"`
 }
 
+// ServiceLoadMetricDescription describes a load metric reported by a service.
+type ServiceLoadMetricDescription struct {
+	Name                 string `json:"Name"`
+	Weight               string `json:"Weight,omitempty"`
+	PrimaryDefaultLoad   *int64 `json:"PrimaryDefaultLoad,omitempty"`
+	SecondaryDefaultLoad *int64 `json:"SecondaryDefaultLoad,omitempty"`
+	DefaultLoad          *int64 `json:"DefaultLoad,omitempty"`
+}
+
+// ServiceGroupMemberDescription describes a member service of a service group.
+// ServiceName is the full member name, fabric:/App/Group#Member.
+type ServiceGroupMemberDescription struct {
+	ServiceTypeName    string                         `json:"ServiceTypeName"`
+	ServiceName        string                         `json:"ServiceName"`
+	ServiceLoadMetrics []ServiceLoadMetricDescription `json:"ServiceLoadMetrics,omitempty"`
+}
+
+// ServiceGroupDescription configures service group creation. Stateless groups
+// set InstanceCount; stateful groups set the replica settings.
+type ServiceGroupDescription struct {
+	ServiceKind                       string                          `json:"ServiceKind"`
+	ApplicationName                   string                          `json:"ApplicationName"`
+	ServiceName                       string                          `json:"ServiceName"`
+	ServiceTypeName                   string                          `json:"ServiceTypeName"`
+	PartitionDescription              PartitionDescription            `json:"PartitionDescription"`
+	PlacementConstraints              string                          `json:"PlacementConstraints,omitempty"`
+	ServiceGroupMemberDescription     []ServiceGroupMemberDescription `json:"ServiceGroupMemberDescription"`
+	InstanceCount                     *int64                          `json:"InstanceCount,omitempty"`
+	TargetReplicaSetSize              *int64                          `json:"TargetReplicaSetSize,omitempty"`
+	MinReplicaSetSize                 *int64                          `json:"MinReplicaSetSize,omitempty"`
+	HasPersistedState                 *bool                           `json:"HasPersistedState,omitempty"`
+	ReplicaRestartWaitDurationSeconds *string                         `json:"ReplicaRestartWaitDurationSeconds,omitempty"`
+	QuorumLossWaitDurationSeconds     *string                         `json:"QuorumLossWaitDurationSeconds,omitempty"`
+	StandByReplicaKeepDurationSeconds *string                         `json:"StandByReplicaKeepDurationSeconds,omitempty"`
+}
+
+// ServiceGroupUpdateDescription defines mutable service group settings. Flags
+// uses the same bits as the corresponding service update description.
+type ServiceGroupUpdateDescription struct {
+	ServiceKind                       string  `json:"ServiceKind"`
+	Flags                             string  `json:"Flags,omitempty"`
+	InstanceCount                     *int64  `json:"InstanceCount,omitempty"`
+	TargetReplicaSetSize              *int64  `json:"TargetReplicaSetSize,omitempty"`
+	MinReplicaSetSize                 *int64  `json:"MinReplicaSetSize,omitempty"`
+	ReplicaRestartWaitDurationSeconds *string `json:"ReplicaRestartWaitDurationSeconds,omitempty"`
+	QuorumLossWaitDurationSeconds     *string `json:"QuorumLossWaitDurationSeconds,omitempty"`
+	StandByReplicaKeepDurationSeconds *string `json:"StandByReplicaKeepDurationSeconds,omitempty"`
+}
+
+// ServiceGroupDescriptionInfo is the description of an existing service group
+// as reported by the cluster.
+type ServiceGroupDescriptionInfo struct {
+	ServiceKind                       string                              `json:"ServiceKind"`
+	ApplicationName                   string                              `json:"ApplicationName"`
+	ServiceName                       string                              `json:"ServiceName"`
+	ServiceTypeName                   string                              `json:"ServiceTypeName"`
+	PartitionDescription              PartitionDescriptionInfo            `json:"PartitionDescription"`
+	PlacementConstraints              string                              `json:"PlacementConstraints"`
+	ServiceGroupMemberDescription     []ServiceGroupMemberDescriptionInfo `json:"ServiceGroupMemberDescription"`
+	InstanceCount                     *Int64String                        `json:"InstanceCount,omitempty"`
+	TargetReplicaSetSize              *Int64String                        `json:"TargetReplicaSetSize,omitempty"`
+	MinReplicaSetSize                 *Int64String                        `json:"MinReplicaSetSize,omitempty"`
+	HasPersistedState                 *bool                               `json:"HasPersistedState,omitempty"`
+	ReplicaRestartWaitDurationSeconds *Int64String                        `json:"ReplicaRestartWaitDurationSeconds,omitempty"`
+	QuorumLossWaitDurationSeconds     *Int64String                        `json:"QuorumLossWaitDurationSeconds,omitempty"`
+	StandByReplicaKeepDurationSeconds *Int64String                        `json:"StandByReplicaKeepDurationSeconds,omitempty"`
+}
+
+// ServiceGroupMemberDescriptionInfo is a member of an existing service group.
+type ServiceGroupMemberDescriptionInfo struct {
+	ServiceTypeName    string                             `json:"ServiceTypeName"`
+	ServiceName        string                             `json:"ServiceName"`
+	ServiceLoadMetrics []ServiceLoadMetricDescriptionInfo `json:"ServiceLoadMetrics"`
+}
+
+// ServiceLoadMetricDescriptionInfo is a load metric of an existing service.
+type ServiceLoadMetricDescriptionInfo struct {
+	Name                 string       `json:"Name"`
+	Weight               string       `json:"Weight"`
+	PrimaryDefaultLoad   *Int64String `json:"PrimaryDefaultLoad,omitempty"`
+	SecondaryDefaultLoad *Int64String `json:"SecondaryDefaultLoad,omitempty"`
+	DefaultLoad          *Int64String `json:"DefaultLoad,omitempty"`
+}
+
+// ServiceDescriptionInfo is the description of an existing service as reported
+// by the cluster. Stateless and stateful fields are populated according to ServiceKind.
+type ServiceDescriptionInfo struct {
+	ServiceKind                        string                   `json:"ServiceKind"`
+	ApplicationName                    string                   `json:"ApplicationName"`
+	ServiceName                        string                   `json:"ServiceName"`
+	ServiceTypeName                    string                   `json:"ServiceTypeName"`
+	PartitionDescription               PartitionDescriptionInfo `json:"PartitionDescription"`
+	PlacementConstraints               string                   `json:"PlacementConstraints"`
+	DefaultMoveCost                    string                   `json:"DefaultMoveCost"`
+	ServicePackageActivationMode       string                   `json:"ServicePackageActivationMode"`
+	ServiceDnsName                     string                   `json:"ServiceDnsName"`
+	InstanceCount                      *Int64String             `json:"InstanceCount,omitempty"`
+	MinInstanceCount                   *Int64String             `json:"MinInstanceCount,omitempty"`
+	MinInstancePercentage              *Int64String             `json:"MinInstancePercentage,omitempty"`
+	InstanceCloseDelayDurationSeconds  *Int64String             `json:"InstanceCloseDelayDurationSeconds,omitempty"`
+	InstanceRestartWaitDurationSeconds *Int64String             `json:"InstanceRestartWaitDurationSeconds,omitempty"`
+	TargetReplicaSetSize               *Int64String             `json:"TargetReplicaSetSize,omitempty"`
+	MinReplicaSetSize                  *Int64String             `json:"MinReplicaSetSize,omitempty"`
+	HasPersistedState                  *bool                    `json:"HasPersistedState,omitempty"`
+	ReplicaRestartWaitDurationSeconds  *Int64String             `json:"ReplicaRestartWaitDurationSeconds,omitempty"`
+	QuorumLossWaitDurationSeconds      *Int64String             `json:"QuorumLossWaitDurationSeconds,omitempty"`
+	StandByReplicaKeepDurationSeconds  *Int64String             `json:"StandByReplicaKeepDurationSeconds,omitempty"`
+	ServicePlacementTimeLimitSeconds   *Int64String             `json:"ServicePlacementTimeLimitSeconds,omitempty"`
+}
+
+// PartitionDescriptionInfo is the partitioning scheme reported for an existing service.
+type PartitionDescriptionInfo struct {
+	PartitionScheme string       `json:"PartitionScheme"`
+	Count           *Int64String `json:"Count,omitempty"`
+	Names           []string     `json:"Names,omitempty"`
+	LowKey          *Int64String `json:"LowKey,omitempty"`
+	HighKey         *Int64String `json:"HighKey,omitempty"`
+}
+
+// Int64String decodes integers that Service Fabric encodes either as JSON
+// numbers or as strings.
+type Int64String int64
+
+// UnmarshalJSON accepts both quoted and unquoted integers.
+func (v *Int64String) UnmarshalJSON(b []byte) error {
+	raw := strings.Trim(strings.TrimSpace(string(b)), "\"")
+	if raw == "" || raw == "null" {
+		return nil
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return fmt.Errorf("decode integer %s: %w", string(b), err)
+	}
+	*v = Int64String(n)
+	return nil
+}
+
 // ServiceTypeInfo describes a service type declared in an application type.
 type ServiceTypeInfo struct {
 	ServiceTypeDescription json.RawMessage `json:"ServiceTypeDescription"`
@@ -997,9 +1689,10 @@ type ArmMetadata struct {
 	ArmResourceID string `json:"ArmResourceId"`
 }
 
-type pagedServiceInfoList struct {
-	Items             []ServiceInfo `json:"Items"`
-	ContinuationToken string        `json:"ContinuationToken"`
+// pagedList is the envelope of every paged Service Fabric list response.
+type pagedList[T any] struct {
+	Items             []T    `json:"Items"`
+	ContinuationToken string `json:"ContinuationToken"`
 }
 
 // NameValueParameter is the common structure used by the Service Fabric API.
@@ -1070,38 +1763,37 @@ func serviceDescriptionBase(desc any) *ServiceDescription {
 	}
 }
 
-func (c *Client) resolveLocation(location string) (string, error) {
+// resolveLocation turns an operation Location header into a URL on endpoint.
+// Absolute locations may name the specific node that accepted the request, so
+// only their path and query are kept and the request goes to the currently
+// healthy endpoint instead.
+func (c *Client) resolveLocation(endpoint *url.URL, location string) (string, error) {
 	loc, err := url.Parse(location)
 	if err != nil {
 		return "", err
 	}
 
-	if !loc.IsAbs() {
-		base := *c.endpoint
-		if loc.Path != "" {
-			if base.Path == "" || base.Path == "/" {
-				base.Path = loc.Path
-			} else {
-				base.Path = strings.TrimSuffix(base.Path, "/") + "/" + strings.TrimPrefix(loc.Path, "/")
-			}
-		}
-		query := base.Query()
-		for k, values := range loc.Query() {
-			for _, v := range values {
-				query.Add(k, v)
-			}
+	base := *endpoint
+	query := base.Query()
+	if loc.IsAbs() {
+		base.Path = loc.Path
+		base.RawPath = loc.RawPath
+		query = url.Values{}
+	} else if loc.Path != "" {
+		if base.Path == "" || base.Path == "/" {
+			base.Path = loc.Path
+		} else {
+			base.Path = strings.TrimSuffix(base.Path, "/") + "/" + strings.TrimPrefix(loc.Path, "/")
 		}
-		if query.Get("api-version") == "" {
-			query.Set("api-version", c.apiVersion)
+	}
+	for k, values := range loc.Query() {
+		for _, v := range values {
+			query.Add(k, v)
 		}
-		base.RawQuery = query.Encode()
-		return base.String(), nil
 	}
-
-	query := loc.Query()
 	if query.Get("api-version") == "" {
 		query.Set("api-version", c.apiVersion)
-		loc.RawQuery = query.Encode()
 	}
-	return loc.String(), nil
+	base.RawQuery = query.Encode()
+	return base.String(), nil
 }
@@ -0,0 +1,58 @@
+package servicefabric
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWaitForApplicationUpgradeStates(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress string
+		wantErr  string
+		idleErr  string
+	}{
+		{
+			name:     "completed",
+			progress: `{"UpgradeState":"RollingForwardCompleted","RollingUpgradeMode":"Monitored"}`,
+		},
+		{
+			name:     "rolled back",
+			progress: `{"UpgradeState":"RollingBackCompleted","UpgradeStatusDetails":"health check failed"}`,
+			wantErr:  "application upgrade failed",
+		},
+		{
+			name:     "paused for a manual step",
+			progress: `{"UpgradeState":"RollingForwardPending","RollingUpgradeMode":"UnmonitoredManual","NextUpgradeDomain":"UD1"}`,
+			wantErr:  `waiting in UnmonitoredManual mode for upgrade domain "UD1"`,
+			idleErr:  `waiting in UnmonitoredManual mode for upgrade domain "UD1"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(tt.progress))
+			}))
+
+			checks := []struct {
+				call    string
+				err     error
+				wantErr string
+			}{
+				{"WaitForApplicationUpgrade", client.WaitForApplicationUpgrade(context.Background(), "fabric:/App"), tt.wantErr},
+				{"WaitForApplicationUpgradeIdle", client.WaitForApplicationUpgradeIdle(context.Background(), "fabric:/App"), tt.idleErr},
+			}
+			for _, check := range checks {
+				switch {
+				case check.wantErr == "" && check.err != nil:
+					t.Errorf("%s() returned error: %s", check.call, check.err)
+				case check.wantErr != "" && (check.err == nil || !strings.Contains(check.err.Error(), check.wantErr)):
+					t.Errorf("%s() error = %v, want it to contain %q", check.call, check.err, check.wantErr)
+				}
+			}
+		})
+	}
+}
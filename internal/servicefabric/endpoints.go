@@ -0,0 +1,131 @@
+package servicefabric
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// endpointSet holds the HTTP gateway endpoints of a cluster and remembers the
+// one that last answered, so requests keep going to a healthy node.
+type endpointSet struct {
+	urls []*url.URL
+
+	mu      sync.Mutex
+	healthy int
+}
+
+func newEndpointSet(raw []string) (*endpointSet, error) {
+	set := &endpointSet{}
+	seen := map[string]bool{}
+	for _, value := range raw {
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		parsed, err := url.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint %q: %w", value, err)
+		}
+		if parsed.Scheme == "" || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid endpoint %q: scheme and host are required", value)
+		}
+		set.urls = append(set.urls, parsed)
+	}
+	if len(set.urls) == 0 {
+		return nil, fmt.Errorf("endpoint required")
+	}
+	return set, nil
+}
+
+func (s *endpointSet) current() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.healthy
+}
+
+func (s *endpointSet) markHealthy(index int) {
+	s.mu.Lock()
+	s.healthy = index
+	s.mu.Unlock()
+}
+
+// Endpoint returns the endpoint requests are currently sent to.
+func (c *Client) Endpoint() string {
+	return c.endpoints.urls[c.endpoints.current()].String()
+}
+
+// send issues the request built by newRequest against the healthy endpoint,
+// failing over to the next endpoint when a node is unreachable or answers 503.
+// Each endpoint is tried at most once per call.
+func (c *Client) send(ctx context.Context, method string, newRequest func(endpoint *url.URL) (*http.Request, error)) (*http.Response, error) {
+	start := c.endpoints.current()
+	count := len(c.endpoints.urls)
+	for attempt := 0; ; attempt++ {
+		index := (start + attempt) % count
+		endpoint := c.endpoints.urls[index]
+		req, err := newRequest(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.httpClient.Do(req)
+		if !shouldFailover(ctx, method, resp, err) {
+			if err == nil && index != start {
+				c.endpoints.markHealthy(index)
+			}
+			return resp, err
+		}
+		if attempt == count-1 {
+			return resp, err
+		}
+
+		reason := "unavailable"
+		if err != nil {
+			reason = err.Error()
+		} else {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		next := c.endpoints.urls[(index+1)%count]
+		trace.SpanFromContext(ctx).AddEvent("endpoint.failover", trace.WithAttributes(
+			attribute.String("servicefabric.endpoint.from", endpoint.Host),
+			attribute.String("servicefabric.endpoint.to", next.Host),
+		))
+		tflog.Warn(ctx, "Service Fabric endpoint unavailable, failing over", map[string]any{
+			"endpoint": endpoint.String(),
+			"next":     next.String(),
+			"reason":   reason,
+		})
+	}
+}
+
+// shouldFailover reports whether a request should be retried on another
+// endpoint. Connection failures qualify for reads; writes only fail over when
+// the connection was never established, so a request the node may already be
+// processing is not sent twice.
+func shouldFailover(ctx context.Context, method string, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err == nil {
+		return resp.StatusCode == http.StatusServiceUnavailable
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	if method != http.MethodGet {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
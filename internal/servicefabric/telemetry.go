@@ -0,0 +1,44 @@
+package servicefabric
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies spans created by the client. Spans go to the
+// global tracer provider, which is a no-op unless the provider binary installs
+// an exporter.
+const instrumentationName = "github.com/williamoconnorme/terraform-provider-servicefabric/internal/servicefabric"
+
+// Span attribute keys.
+const (
+	attrApplicationTypeName    = attribute.Key("servicefabric.application_type.name")
+	attrApplicationTypeVersion = attribute.Key("servicefabric.application_type.version")
+	attrApplicationTypeStatus  = attribute.Key("servicefabric.application_type.status")
+	attrApplicationName        = attribute.Key("servicefabric.application.name")
+	attrServiceName            = attribute.Key("servicefabric.service.name")
+	attrServiceTypeName        = attribute.Key("servicefabric.service_type.name")
+	attrOperationID            = attribute.Key("servicefabric.operation.id")
+	attrOperationState         = attribute.Key("servicefabric.operation.state")
+	attrUpgradeState           = attribute.Key("servicefabric.upgrade.state")
+	attrPollAttempts           = attribute.Key("servicefabric.poll.attempts")
+	attrResultCount            = attribute.Key("servicefabric.result.count")
+	attrAPIVersion             = attribute.Key("servicefabric.api_version")
+)
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, "servicefabric."+name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err on span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
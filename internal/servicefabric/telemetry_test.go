@@ -0,0 +1,196 @@
+package servicefabric
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs an in-memory exporter as the global tracer provider for
+// the duration of the test.
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := NewClient(ClientConfig{Endpoint: server.URL, ListCacheTTL: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	t.Fatalf("span %s not recorded; got %d spans", name, len(spans))
+	return tracetest.SpanStub{}
+}
+
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestClientSpans(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		call       func(context.Context, *Client) error
+		span       string
+		wantStatus codes.Code
+		wantAttrs  map[attribute.Key]attribute.Value
+		wantEvents []string
+	}{
+		{
+			name: "successful read",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"Name":"fabric:/App","TypeName":"AppType","TypeVersion":"1.0.0"}`))
+			},
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetApplication(ctx, "fabric:/App")
+				return err
+			},
+			span:       "servicefabric.GetApplication",
+			wantStatus: codes.Unset,
+			wantAttrs: map[attribute.Key]attribute.Value{
+				attrApplicationName: attribute.StringValue("fabric:/App"),
+			},
+			wantEvents: []string{"http.request"},
+		},
+		{
+			name: "failed read",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"Error":{"Code":"FABRIC_E_APPLICATION_NOT_FOUND","Message":"not found"}}`))
+			},
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetApplication(ctx, "fabric:/Missing")
+				return err
+			},
+			span:       "servicefabric.GetApplication",
+			wantStatus: codes.Error,
+			wantAttrs: map[attribute.Key]attribute.Value{
+				attrApplicationName: attribute.StringValue("fabric:/Missing"),
+			},
+			wantEvents: []string{"http.request", "exception"},
+		},
+		{
+			name: "list result count",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"Items":[{"Name":"AppType","Version":"1.0.0"},{"Name":"AppType","Version":"2.0.0"}]}`))
+			},
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.ListApplicationTypeVersions(ctx, "AppType")
+				return err
+			},
+			span:       "servicefabric.ListApplicationTypeVersions",
+			wantStatus: codes.Unset,
+			wantAttrs: map[attribute.Key]attribute.Value{
+				attrApplicationTypeName: attribute.StringValue("AppType"),
+				attrResultCount:         attribute.IntValue(2),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := recordSpans(t)
+			client := newTestClient(t, tt.handler)
+
+			err := tt.call(context.Background(), client)
+			if (err != nil) != (tt.wantStatus == codes.Error) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			span := findSpan(t, exporter.GetSpans(), tt.span)
+			if span.Status.Code != tt.wantStatus {
+				t.Errorf("status = %v, want %v", span.Status.Code, tt.wantStatus)
+			}
+			for key, want := range tt.wantAttrs {
+				got, ok := spanAttribute(span, key)
+				if !ok || got != want {
+					t.Errorf("attribute %s = %v, want %v", key, got.Emit(), want.Emit())
+				}
+			}
+			for _, want := range tt.wantEvents {
+				found := false
+				for _, event := range span.Events {
+					if event.Name == want {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("event %s not recorded on %s", want, tt.span)
+				}
+			}
+		})
+	}
+}
+
+func TestClientSpansNestOperationPolling(t *testing.T) {
+	exporter := recordSpans(t)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/Applications/App/$/Delete":
+			w.Header().Set("Location", "/Operations/42")
+			w.WriteHeader(http.StatusAccepted)
+		case "/Operations/42":
+			_, _ = w.Write([]byte(`{"Id":"42","Status":"Succeeded"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"Error":{"Code":"FABRIC_E_APPLICATION_NOT_FOUND"}}`))
+		}
+	}))
+
+	if err := client.DeleteApplication(context.Background(), "fabric:/App", false); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	root := findSpan(t, spans, "servicefabric.DeleteApplication")
+	poll := findSpan(t, spans, "servicefabric.pollOperation")
+	wait := findSpan(t, spans, "servicefabric.waitForApplicationDeleted")
+
+	if root.Status.Code != codes.Unset {
+		t.Errorf("DeleteApplication status = %v, want Unset", root.Status.Code)
+	}
+	for _, child := range []tracetest.SpanStub{poll, wait} {
+		if child.Parent.SpanID() != root.SpanContext.SpanID() {
+			t.Errorf("span %s is not a child of DeleteApplication", child.Name)
+		}
+	}
+	if got, _ := spanAttribute(poll, attrOperationState); got.AsString() != "Succeeded" {
+		t.Errorf("operation state = %q, want Succeeded", got.AsString())
+	}
+	if got, _ := spanAttribute(poll, attrPollAttempts); got.AsInt64() != 1 {
+		t.Errorf("poll attempts = %d, want 1", got.AsInt64())
+	}
+}
@@ -0,0 +1,184 @@
+package servicefabric
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ServerCertificateOptions describes how the cluster's server certificate is
+// validated, mirroring the X509Credentials used by sfctl and the Service Fabric
+// SDK (ServerCertThumbprints, RemoteCommonNames and IssuerThumbprints).
+type ServerCertificateOptions struct {
+	// Thumbprints pins the server certificate itself. SHA-1 (as shown by
+	// Service Fabric) and SHA-256 thumbprints are accepted.
+	Thumbprints []string
+	// CommonNames accepts any certificate whose subject common name matches.
+	CommonNames []string
+	// IssuerThumbprints restricts CommonNames matches to certificates issued by
+	// one of the given issuers. Without it the chain must be trusted by the
+	// roots in CACertificatePath or, when unset, the system roots.
+	IssuerThumbprints []string
+	// CACertificatePath is a PEM bundle of trusted roots. When set it replaces
+	// the system roots rather than adding to them.
+	CACertificatePath string
+}
+
+func (o ServerCertificateOptions) pinned() bool {
+	return len(o.Thumbprints) > 0 || len(o.CommonNames) > 0
+}
+
+// ConfigureServerCertificateValidation applies opts to the client's TLS
+// configuration. When thumbprints or common names are configured, Go's default
+// hostname verification is replaced by Service Fabric's matching rules.
+func ConfigureServerCertificateValidation(client *http.Client, opts ServerCertificateOptions) error {
+	if !opts.pinned() && opts.CACertificatePath == "" {
+		return nil
+	}
+
+	var roots *x509.CertPool
+	if opts.CACertificatePath != "" {
+		pem, err := os.ReadFile(opts.CACertificatePath)
+		if err != nil {
+			return fmt.Errorf("read CA certificate: %w", err)
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no PEM certificates found in %s", opts.CACertificatePath)
+		}
+	}
+
+	transport, err := ensureTransport(client)
+	if err != nil {
+		return err
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.RootCAs = roots
+	if !opts.pinned() {
+		return nil
+	}
+
+	verifier := &serverCertificateVerifier{
+		thumbprints:       normalizeThumbprints(opts.Thumbprints),
+		commonNames:       opts.CommonNames,
+		issuerThumbprints: normalizeThumbprints(opts.IssuerThumbprints),
+		roots:             roots,
+	}
+	// Chain and hostname checks are performed by the verifier instead.
+	transport.TLSClientConfig.InsecureSkipVerify = true
+	transport.TLSClientConfig.VerifyPeerCertificate = verifier.verify
+	return nil
+}
+
+type serverCertificateVerifier struct {
+	thumbprints       map[string]struct{}
+	commonNames       []string
+	issuerThumbprints map[string]struct{}
+	roots             *x509.CertPool
+}
+
+func (v *serverCertificateVerifier) verify(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return errors.New("server presented no certificate")
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("parse server certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	leaf := certs[0]
+
+	if matchesThumbprint(leaf, v.thumbprints) {
+		return nil
+	}
+
+	if v.matchesCommonName(leaf) {
+		if len(v.issuerThumbprints) > 0 {
+			issuer := leaf
+			if len(certs) > 1 {
+				issuer = certs[1]
+			}
+			if err := leaf.CheckSignatureFrom(issuer); err != nil {
+				return fmt.Errorf("server certificate %q is not signed by the presented issuer: %w", leaf.Subject.CommonName, err)
+			}
+			if !matchesThumbprint(issuer, v.issuerThumbprints) {
+				return fmt.Errorf("server certificate %q was issued by %q (thumbprint %s), which is not an allowed issuer", leaf.Subject.CommonName, issuer.Subject.CommonName, Thumbprint(issuer))
+			}
+			return nil
+		}
+
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		if _, err := leaf.Verify(x509.VerifyOptions{
+			Roots:         v.roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}); err != nil {
+			return fmt.Errorf("server certificate %q matched an allowed common name but its chain is not trusted: %w", leaf.Subject.CommonName, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("server certificate %q (thumbprint %s) matches neither the configured thumbprints nor common names", leaf.Subject.CommonName, Thumbprint(leaf))
+}
+
+func (v *serverCertificateVerifier) matchesCommonName(cert *x509.Certificate) bool {
+	for _, name := range v.commonNames {
+		if strings.EqualFold(strings.TrimSpace(name), cert.Subject.CommonName) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesThumbprint(cert *x509.Certificate, thumbprints map[string]struct{}) bool {
+	if len(thumbprints) == 0 {
+		return false
+	}
+	if _, ok := thumbprints[Thumbprint(cert)]; ok {
+		return true
+	}
+	sum := sha256.Sum256(cert.Raw)
+	_, ok := thumbprints[strings.ToUpper(hex.EncodeToString(sum[:]))]
+	return ok
+}
+
+// Thumbprint returns the SHA-1 thumbprint of cert in the uppercase hex form
+// shown by Service Fabric and Windows certificate tooling.
+func Thumbprint(cert *x509.Certificate) string {
+	sum := sha1.Sum(cert.Raw)
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// normalizeThumbprints uppercases thumbprints and strips the separators and
+// direction marks commonly copied from certificate viewers.
+func normalizeThumbprints(values []string) map[string]struct{} {
+	out := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		cleaned := strings.Map(func(r rune) rune {
+			switch r {
+			case ' ', ':', '-', '\u200e', '\u200f':
+				return -1
+			}
+			return r
+		}, value)
+		if cleaned != "" {
+			out[strings.ToUpper(cleaned)] = struct{}{}
+		}
+	}
+	return out
+}
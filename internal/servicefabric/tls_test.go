@@ -0,0 +1,201 @@
+package servicefabric
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCertificate issues a certificate for commonName signed by parent, or
+// a self-signed CA certificate when parent is nil.
+func newTestCertificate(t *testing.T, commonName string, parent *testCertificate) *testCertificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCertificate{cert: cert, key: key}
+}
+
+func rawChain(certs ...*testCertificate) [][]byte {
+	raw := make([][]byte, 0, len(certs))
+	for _, c := range certs {
+		raw = append(raw, c.cert.Raw)
+	}
+	return raw
+}
+
+func TestServerCertificateVerifier(t *testing.T) {
+	ca := newTestCertificate(t, "Contoso Root", nil)
+	otherCA := newTestCertificate(t, "Other Root", nil)
+	leaf := newTestCertificate(t, "cluster.contoso.com", ca)
+	forged := newTestCertificate(t, "cluster.contoso.com", otherCA)
+	selfSigned := newTestCertificate(t, "cluster.contoso.com", nil)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	// Thumbprints as copied from a certificate viewer: lowercase with colons.
+	colonThumbprint := strings.ToLower(Thumbprint(selfSigned.cert))
+	for i := len(colonThumbprint) - 2; i > 0; i -= 2 {
+		colonThumbprint = colonThumbprint[:i] + ":" + colonThumbprint[i:]
+	}
+	sha256Sum := sha256.Sum256(selfSigned.cert.Raw)
+
+	tests := []struct {
+		name     string
+		verifier serverCertificateVerifier
+		chain    [][]byte
+		wantErr  string
+	}{
+		{
+			name:     "no certificate",
+			verifier: serverCertificateVerifier{thumbprints: normalizeThumbprints([]string{Thumbprint(leaf.cert)})},
+			wantErr:  "presented no certificate",
+		},
+		{
+			name:     "SHA-1 thumbprint with separators",
+			verifier: serverCertificateVerifier{thumbprints: normalizeThumbprints([]string{colonThumbprint})},
+			chain:    rawChain(selfSigned),
+		},
+		{
+			name:     "SHA-256 thumbprint",
+			verifier: serverCertificateVerifier{thumbprints: normalizeThumbprints([]string{hex.EncodeToString(sha256Sum[:])})},
+			chain:    rawChain(selfSigned),
+		},
+		{
+			name:     "thumbprint mismatch",
+			verifier: serverCertificateVerifier{thumbprints: normalizeThumbprints([]string{Thumbprint(leaf.cert)})},
+			chain:    rawChain(selfSigned),
+			wantErr:  "matches neither the configured thumbprints nor common names",
+		},
+		{
+			name: "common name with allowed issuer",
+			verifier: serverCertificateVerifier{
+				commonNames:       []string{"CLUSTER.contoso.com"},
+				issuerThumbprints: normalizeThumbprints([]string{Thumbprint(ca.cert)}),
+			},
+			chain: rawChain(leaf, ca),
+		},
+		{
+			name: "common name with wrong issuer",
+			verifier: serverCertificateVerifier{
+				commonNames:       []string{"cluster.contoso.com"},
+				issuerThumbprints: normalizeThumbprints([]string{Thumbprint(ca.cert)}),
+			},
+			chain:   rawChain(forged, otherCA),
+			wantErr: "which is not an allowed issuer",
+		},
+		{
+			name: "common name with issuer that did not sign it",
+			verifier: serverCertificateVerifier{
+				commonNames:       []string{"cluster.contoso.com"},
+				issuerThumbprints: normalizeThumbprints([]string{Thumbprint(ca.cert)}),
+			},
+			chain:   rawChain(forged, ca),
+			wantErr: "is not signed by the presented issuer",
+		},
+		{
+			name:     "common name trusted by roots",
+			verifier: serverCertificateVerifier{commonNames: []string{"cluster.contoso.com"}, roots: roots},
+			chain:    rawChain(leaf),
+		},
+		{
+			name:     "common name with untrusted chain",
+			verifier: serverCertificateVerifier{commonNames: []string{"cluster.contoso.com"}, roots: roots},
+			chain:    rawChain(forged, otherCA),
+			wantErr:  "its chain is not trusted",
+		},
+		{
+			name:     "common name mismatch",
+			verifier: serverCertificateVerifier{commonNames: []string{"other.contoso.com"}, roots: roots},
+			chain:    rawChain(leaf),
+			wantErr:  "matches neither the configured thumbprints nor common names",
+		},
+		{
+			name:     "malformed certificate",
+			verifier: serverCertificateVerifier{commonNames: []string{"cluster.contoso.com"}},
+			chain:    [][]byte{[]byte("not a certificate")},
+			wantErr:  "parse server certificate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.verifier.verify(tt.chain, nil)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("verify() returned error: %s", err)
+			case tt.wantErr != "" && err == nil:
+				t.Fatalf("verify() succeeded, want error containing %q", tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Fatalf("verify() error = %q, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigureServerCertificateValidationReplacesSystemRoots(t *testing.T) {
+	ca := newTestCertificate(t, "Contoso Root", nil)
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{}
+	if err := ConfigureServerCertificateValidation(client, ServerCertificateOptions{CACertificatePath: bundle}); err != nil {
+		t.Fatal(err)
+	}
+	config := client.Transport.(*http.Transport).TLSClientConfig
+	if config.InsecureSkipVerify || config.VerifyPeerCertificate != nil {
+		t.Fatal("CA bundle alone must keep standard verification")
+	}
+	if subjects := config.RootCAs.Subjects(); len(subjects) != 1 {
+		t.Fatalf("RootCAs holds %d subjects, want only the bundle's root", len(subjects))
+	}
+}
@@ -0,0 +1,85 @@
+package servicefabric
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// tokenRefreshAhead is how long before expiry a cached token is renewed
+	// when the credential does not suggest a refresh time itself.
+	tokenRefreshAhead = 5 * time.Minute
+	// tokenMinimumLifetime is the remaining lifetime below which a cached token
+	// is no longer used even if renewing it fails.
+	tokenMinimumLifetime = 30 * time.Second
+)
+
+// tokenCache keeps the current access token for one scope so requests and
+// polls do not each go through the credential. Tokens are renewed ahead of
+// expiry; if renewal fails while the old token is still usable, the old
+// token is served and renewal is retried on the next request.
+type tokenCache struct {
+	cred     azcore.TokenCredential
+	credName string
+	scope    string
+
+	mu      sync.Mutex
+	current azcore.AccessToken
+}
+
+func newTokenCache(cred azcore.TokenCredential, credName, scope string) *tokenCache {
+	return &tokenCache{cred: cred, credName: credName, scope: scope}
+}
+
+func (c *tokenCache) token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.current.Token != "" && now.Before(c.refreshAt()) {
+		return c.current.Token, nil
+	}
+
+	token, err := c.cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{c.scope}})
+	if err != nil {
+		err = c.describeError(err)
+		if c.current.Token != "" && c.current.ExpiresOn.Sub(now) > tokenMinimumLifetime {
+			tflog.Warn(ctx, "Renewing Entra token failed; using the cached token until it expires", map[string]any{
+				"expiresOn": c.current.ExpiresOn,
+				"error":     err.Error(),
+			})
+			return c.current.Token, nil
+		}
+		return "", err
+	}
+	c.current = token
+	return token.Token, nil
+}
+
+func (c *tokenCache) refreshAt() time.Time {
+	if !c.current.RefreshOn.IsZero() {
+		return c.current.RefreshOn
+	}
+	return c.current.ExpiresOn.Add(-tokenRefreshAhead)
+}
+
+// describeError names the credential that failed and what to check. The
+// DefaultAzureCredential error already lists every credential it attempted.
+func (c *tokenCache) describeError(err error) error {
+	hint := ""
+	var authErr *azidentity.AuthenticationFailedError
+	if errors.As(err, &authErr) {
+		hint = "\nEntra ID rejected the request: check tenant_id, client_id and the credential, and that cluster_application_id is the cluster's server application ID."
+	} else if c.credName == "DefaultAzureCredential" {
+		hint = "\nNone of the credentials in the DefaultAzureCredential chain could authenticate; the reason for each is listed above. Set default_credential_type to try a single credential."
+	}
+	return fmt.Errorf("acquire Entra token for %s using %s: %w%s", c.scope, c.credName, err, hint)
+}
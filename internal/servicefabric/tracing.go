@@ -0,0 +1,221 @@
+package servicefabric
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// TracingSubsystem is the tflog subsystem HTTP traces are written to. Its level
+// can be raised independently with TF_LOG_PROVIDER_SERVICEFABRIC_HTTP.
+const TracingSubsystem = "http"
+
+const (
+	redactedValue = "REDACTED"
+	// maxTracedBodyBytes bounds how much of a request or response body is logged.
+	maxTracedBodyBytes = 64 * 1024
+)
+
+// defaultRedactedParameters are application parameter name patterns whose
+// values are always redacted from traces.
+var defaultRedactedParameters = []string{"*password*", "*secret*", "*token*", "*connectionstring*", "*accountkey*"}
+
+var sasSignaturePattern = regexp.MustCompile(`(?i)([?&]sig=)[^&"\s]+`)
+
+// TracingOptions configures NewTracingTransport.
+type TracingOptions struct {
+	// RedactedParameters are glob patterns (case-insensitive) of application
+	// parameter names whose values must not be logged, in addition to the
+	// built-in patterns.
+	RedactedParameters []string
+	// Secrets are literal values, such as certificate passwords or client
+	// secrets, replaced wherever they appear in a trace.
+	Secrets []string
+	// SecretFiles are files, such as a federated token file, whose contents
+	// are treated as secrets. They are read again before every request since
+	// their contents may be rotated.
+	SecretFiles []string
+}
+
+// tracingTransport logs every request and response to the TracingSubsystem.
+type tracingTransport struct {
+	next        http.RoundTripper
+	parameters  []string
+	secrets     []string
+	secretFiles []string
+	fileSecrets atomic.Pointer[[]string]
+}
+
+// NewTracingTransport wraps next with an opt-in tracing layer that logs method,
+// URL, status, latency, bodies and the Fabric error code with secrets redacted.
+func NewTracingTransport(next http.RoundTripper, opts TracingOptions) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	parameters := append([]string{}, defaultRedactedParameters...)
+	for _, p := range opts.RedactedParameters {
+		parameters = append(parameters, strings.ToLower(p))
+	}
+	var secrets []string
+	for _, s := range opts.Secrets {
+		if s != "" {
+			secrets = append(secrets, s)
+		}
+	}
+	return &tracingTransport{next: next, parameters: parameters, secrets: secrets, secretFiles: opts.SecretFiles}
+}
+
+// readSecretFiles loads the current contents of the secret files. Files that
+// cannot be read are skipped; the authenticator reports those errors.
+func (t *tracingTransport) readSecretFiles() {
+	if len(t.secretFiles) == 0 {
+		return
+	}
+	var secrets []string
+	for _, name := range t.secretFiles {
+		if content, err := os.ReadFile(name); err == nil {
+			if secret := strings.TrimSpace(string(content)); secret != "" {
+				secrets = append(secrets, secret)
+			}
+		}
+	}
+	t.fileSecrets.Store(&secrets)
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := tflog.NewSubsystem(req.Context(), TracingSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_SERVICEFABRIC_HTTP"))
+	t.readSecretFiles()
+
+	var requestBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		requestBody = body
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	fields := map[string]any{
+		"method": req.Method,
+		"url":    t.redactString(req.URL.String()),
+	}
+	if req.Header.Get("Authorization") != "" {
+		fields["authorization"] = redactedValue
+	}
+	if len(requestBody) > 0 {
+		fields["request_body"] = t.redactBody(requestBody)
+	}
+	tflog.SubsystemDebug(ctx, TracingSubsystem, "Sending Service Fabric request", fields)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	fields["latency_ms"] = time.Since(start).Milliseconds()
+	delete(fields, "request_body")
+	delete(fields, "authorization")
+	if err != nil {
+		fields["error"] = t.redactString(err.Error())
+		tflog.SubsystemDebug(ctx, TracingSubsystem, "Service Fabric request failed", fields)
+		return nil, err
+	}
+
+	fields["status"] = resp.StatusCode
+	if resp.Body != nil {
+		body, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if readErr != nil {
+			return nil, readErr
+		}
+		if len(body) > 0 {
+			fields["response_body"] = t.redactBody(body)
+		}
+		if resp.StatusCode >= 400 {
+			var fabricErr struct {
+				Error struct {
+					Code string `json:"Code"`
+				} `json:"Error"`
+			}
+			if json.Unmarshal(body, &fabricErr) == nil && fabricErr.Error.Code != "" {
+				fields["fabric_error_code"] = fabricErr.Error.Code
+			}
+		}
+	}
+	tflog.SubsystemDebug(ctx, TracingSubsystem, "Received Service Fabric response", fields)
+	return resp, nil
+}
+
+// redactBody removes SAS signatures, sensitive application parameter values
+// and known secrets from a JSON body. Non-JSON bodies get string redaction only.
+func (t *tracingTransport) redactBody(body []byte) string {
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err == nil {
+		var encoded bytes.Buffer
+		encoder := json.NewEncoder(&encoded)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(t.redactValue(decoded)); err == nil {
+			body = bytes.TrimSpace(encoded.Bytes())
+		}
+	}
+	if len(body) > maxTracedBodyBytes {
+		body = append(body[:maxTracedBodyBytes:maxTracedBodyBytes], []byte("...(truncated)")...)
+	}
+	return t.redactString(string(body))
+}
+
+func (t *tracingTransport) redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		// Application parameters are sent as {"Key": ..., "Value": ...} pairs.
+		if key, ok := v["Key"].(string); ok {
+			if _, hasValue := v["Value"]; hasValue && t.sensitiveParameter(key) {
+				v["Value"] = redactedValue
+			}
+		}
+		for k, item := range v {
+			v[k] = t.redactValue(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = t.redactValue(item)
+		}
+		return v
+	case string:
+		return t.redactString(v)
+	}
+	return value
+}
+
+func (t *tracingTransport) sensitiveParameter(name string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range t.parameters {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *tracingTransport) redactString(s string) string {
+	s = sasSignaturePattern.ReplaceAllString(s, "${1}"+redactedValue)
+	for _, secret := range t.secrets {
+		s = strings.ReplaceAll(s, secret, redactedValue)
+	}
+	if fileSecrets := t.fileSecrets.Load(); fileSecrets != nil {
+		for _, secret := range *fileSecrets {
+			s = strings.ReplaceAll(s, secret, redactedValue)
+		}
+	}
+	return s
+}
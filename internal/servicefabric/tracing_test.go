@@ -0,0 +1,150 @@
+package servicefabric
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTracingTransportRedactBody(t *testing.T) {
+	transport := NewTracingTransport(nil, TracingOptions{
+		RedactedParameters: []string{"Custom*"},
+		Secrets:            []string{"hunter2", ""},
+	}).(*tracingTransport)
+
+	tests := []struct {
+		name     string
+		body     string
+		want     []string
+		mustDrop []string
+	}{
+		{
+			name:     "sensitive application parameter",
+			body:     `{"ParameterList":[{"Key":"DbPassword","Value":"p@ss"},{"Key":"InstanceCount","Value":"3"}]}`,
+			want:     []string{`{"Key":"DbPassword","Value":"REDACTED"}`, `{"Key":"InstanceCount","Value":"3"}`},
+			mustDrop: []string{"p@ss"},
+		},
+		{
+			name:     "built-in patterns are case-insensitive",
+			body:     `{"Parameters":[{"Key":"Storage_ConnectionString","Value":"AccountKey=abc"},{"Key":"API_TOKEN","Value":"tok"}]}`,
+			want:     []string{`"Value":"REDACTED"`},
+			mustDrop: []string{"AccountKey=abc", `"tok"`},
+		},
+		{
+			name:     "configured parameter pattern",
+			body:     `[{"Key":"CustomSetting","Value":"x1"},{"Key":"Other","Value":"x2"}]`,
+			want:     []string{`{"Key":"CustomSetting","Value":"REDACTED"}`, `{"Key":"Other","Value":"x2"}`},
+			mustDrop: []string{"x1"},
+		},
+		{
+			name:     "SAS signature in nested string",
+			body:     `{"ApplicationTypeBuildPath":"https://acct.blob.core.windows.net/apps/app.sfpkg?sv=2022&sig=abc%2Fdef&se=2030"}`,
+			want:     []string{"sig=REDACTED&se=2030"},
+			mustDrop: []string{"abc%2Fdef"},
+		},
+		{
+			name:     "literal secret in JSON",
+			body:     `{"Description":"password is hunter2"}`,
+			want:     []string{`"password is REDACTED"`},
+			mustDrop: []string{"hunter2"},
+		},
+		{
+			name:     "non-JSON body",
+			body:     "client_secret=hunter2&resource=https://example.net/?sig=zzz",
+			want:     []string{"client_secret=REDACTED", "sig=REDACTED"},
+			mustDrop: []string{"hunter2", "zzz"},
+		},
+		{
+			name: "HTML characters are not escaped",
+			body: `{"Name":"fabric:/App<1>&more"}`,
+			want: []string{`{"Name":"fabric:/App<1>&more"}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := transport.redactBody([]byte(tt.body))
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("redactBody() = %s, want it to contain %s", got, want)
+				}
+			}
+			for _, secret := range tt.mustDrop {
+				if strings.Contains(got, secret) {
+					t.Errorf("redactBody() = %s, leaked %s", got, secret)
+				}
+			}
+		})
+	}
+}
+
+func TestTracingTransportRedactBodyTruncates(t *testing.T) {
+	transport := NewTracingTransport(nil, TracingOptions{}).(*tracingTransport)
+
+	got := transport.redactBody([]byte(strings.Repeat("a", maxTracedBodyBytes+10)))
+	if !strings.HasSuffix(got, "...(truncated)") || len(got) != maxTracedBodyBytes+len("...(truncated)") {
+		t.Fatalf("redactBody() returned %d bytes, want a truncated body", len(got))
+	}
+}
+
+func TestTracingTransportRedactsSecretFiles(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	transport := NewTracingTransport(nil, TracingOptions{
+		SecretFiles: []string{tokenFile, filepath.Join(t.TempDir(), "missing")},
+	}).(*tracingTransport)
+
+	for _, token := range []string{"first-token", "rotated-token"} {
+		if err := os.WriteFile(tokenFile, []byte(token+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		transport.readSecretFiles()
+		got := transport.redactBody([]byte("client_assertion=" + token))
+		if got != "client_assertion=REDACTED" {
+			t.Errorf("redactBody() = %q, want the token from %s redacted", got, tokenFile)
+		}
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTracingTransportPreservesBodies(t *testing.T) {
+	const requestBody = `{"Key":"DbPassword","Value":"p@ss"}`
+	const responseBody = `{"Error":{"Code":"FABRIC_E_APPLICATION_NOT_FOUND"}}`
+
+	transport := NewTracingTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Fatalf("reading request body: %s", err)
+		}
+		if string(body) != requestBody {
+			t.Errorf("request body = %s, want %s", body, requestBody)
+		}
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(strings.NewReader(responseBody)),
+		}, nil
+	}), TracingOptions{})
+
+	req, err := http.NewRequest(http.MethodPost, "https://cluster:19080/Applications/$/Create?sig=abc", strings.NewReader(requestBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != responseBody {
+		t.Errorf("response body = %s, want %s", body, responseBody)
+	}
+}
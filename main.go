@@ -10,9 +10,19 @@ import (
 
 // main is the Terraform provider entrypoint.
 func main() {
-	err := providerserver.Serve(context.Background(), provider.New, providerserver.ServeOpts{
+	ctx := context.Background()
+
+	shutdownTelemetry, err := provider.SetupTelemetry(ctx)
+	if err != nil {
+		log.Printf("[WARN] OpenTelemetry tracing disabled: %v", err)
+	}
+
+	err = providerserver.Serve(ctx, provider.New, providerserver.ServeOpts{
 		Address: "registry.terraform.io/williamoconnorme/servicefabric",
 	})
+	if shutdownErr := shutdownTelemetry(ctx); shutdownErr != nil {
+		log.Printf("[WARN] flushing OpenTelemetry spans failed: %v", shutdownErr)
+	}
 	if err != nil {
 		log.Fatalf("provider server failed: %v", err)
 	}